@@ -32,5 +32,8 @@ type CheckRequest struct {
 	HTTPClient  *http.Client
 	RepoClient  clients.RepoClient
 	Dlogger     DetailLogger
+	// Host is the host of the repo, e.g. github.com or a GitHub Enterprise Server host.
+	// It is never empty.
+	Host        string
 	Owner, Repo string
 }
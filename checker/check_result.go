@@ -18,6 +18,8 @@ package checker
 import (
 	"fmt"
 	"math"
+
+	sce "github.com/ossf/scorecard/v2/errors"
 )
 
 // UPGRADEv2: to remove.
@@ -72,6 +74,9 @@ type CheckResult struct {
 	Details    []string
 	Confidence int
 	Pass       bool
+	// ErrorClass is the name of the class of the runtime error of the check, if any,
+	// e.g. `ErrRateLimited`. See errors.GetName.
+	ErrorClass string `json:",omitempty"`
 
 	// UPGRADEv2: New structure. Omitting unchanged Name field
 	// for simplicity.
@@ -214,5 +219,7 @@ func CreateRuntimeErrorResult(name string, e error) CheckResult {
 		Error2:  e,
 		Score:   InconclusiveResultScore,
 		Reason:  e.Error(), // Note: message already accessible by caller thru `Error`.
+		// Runtime errors are classified for callers which cannot inspect them.
+		ErrorClass: sce.GetName(e),
 	}
 }
@@ -47,7 +47,7 @@ func CITests(c *checker.CheckRequest) checker.CheckResult {
 		State: "closed",
 	})
 	if err != nil {
-		e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.PullRequests.List: %v", err))
+		e := sce.CreateFrom(err, "Client.PullRequests.List")
 		return checker.CreateRuntimeErrorResult(CheckCITests, e)
 	}
 
@@ -108,7 +108,7 @@ func prHasSuccessStatus(pr *github.PullRequest, c *checker.CheckRequest) (bool,
 		&github.ListOptions{})
 	if err != nil {
 		//nolint
-		return false, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Repositories.ListStatuses: %v", err))
+		return false, sce.CreateFrom(err, "Client.Repositories.ListStatuses")
 	}
 
 	for _, status := range statuses {
@@ -130,7 +130,7 @@ func prHasSuccessfulCheck(pr *github.PullRequest, c *checker.CheckRequest) (bool
 		&github.ListCheckRunsOptions{})
 	if err != nil {
 		//nolint
-		return false, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Checks.ListCheckRunsForRef: %v", err))
+		return false, sce.CreateFrom(err, "Client.Checks.ListCheckRunsForRef")
 	}
 	if crs == nil {
 		//nolint
@@ -39,7 +39,7 @@ type response struct {
 
 // CIIBestPractices runs CII-Best-Practices check.
 func CIIBestPractices(c *checker.CheckRequest) checker.CheckResult {
-	repoURL := fmt.Sprintf("https://github.com/%s/%s", c.Owner, c.Repo)
+	repoURL := fmt.Sprintf("https://%s/%s/%s", c.Host, c.Owner, c.Repo)
 	url := fmt.Sprintf("https://bestpractices.coreinfrastructure.org/projects.json?url=%s", repoURL)
 	req, err := http.NewRequestWithContext(c.Ctx, "GET", url, nil)
 	if err != nil {
@@ -48,7 +48,7 @@ func CIIBestPractices(c *checker.CheckRequest) checker.CheckResult {
 	}
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("HTTPClient.Do: %v", err))
+		e := sce.CreateFrom(err, "HTTPClient.Do")
 		return checker.CreateRuntimeErrorResult(CheckCIIBestPractices, e)
 	}
 	defer resp.Body.Close()
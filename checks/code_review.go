@@ -89,7 +89,7 @@ func githubCodeReview(c *checker.CheckRequest) (int, string, error) {
 	totalReviewed := 0
 	prs, err := c.RepoClient.ListMergedPRs()
 	if err != nil {
-		return 0, "", sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("RepoClient.ListMergedPRs: %v", err))
+		return 0, "", sce.CreateFrom(err, "RepoClient.ListMergedPRs")
 	}
 	for _, pr := range prs {
 		if pr.MergedAt.IsZero() {
@@ -145,7 +145,7 @@ func prowCodeReview(c *checker.CheckRequest) (int, string, error) {
 	totalReviewed := 0
 	prs, err := c.RepoClient.ListMergedPRs()
 	if err != nil {
-		sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("RepoClient.ListMergedPRs: %v", err))
+		sce.CreateFrom(err, "RepoClient.ListMergedPRs")
 	}
 	for _, pr := range prs {
 		if pr.MergedAt.IsZero() {
@@ -169,7 +169,7 @@ func commitMessageHints(c *checker.CheckRequest) (int, string, error) {
 	if err != nil {
 		// nolint: wrapcheck
 		return checker.InconclusiveResultScore, "",
-			sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Repositories.ListCommits: %v", err))
+			sce.CreateFrom(err, "RepoClient.ListCommits")
 	}
 
 	total := 0
@@ -40,7 +40,7 @@ func init() {
 func Contributors(c *checker.CheckRequest) checker.CheckResult {
 	contribs, _, err := c.Client.Repositories.ListContributors(c.Ctx, c.Owner, c.Repo, &github.ListContributorsOptions{})
 	if err != nil {
-		e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Repositories.ListContributors: %v", err))
+		e := sce.CreateFrom(err, "Client.Repositories.ListContributors")
 		return checker.CreateRuntimeErrorResult(CheckContributors, e)
 	}
 
@@ -51,7 +51,7 @@ func Contributors(c *checker.CheckRequest) checker.CheckResult {
 		}
 		u, _, err := c.Client.Users.Get(c.Ctx, contrib.GetLogin())
 		if err != nil {
-			e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Users.Get: %v", err))
+			e := sce.CreateFrom(err, "Client.Users.Get")
 			return checker.CreateRuntimeErrorResult(CheckContributors, e)
 		}
 		orgs, _, err := c.Client.Organizations.List(c.Ctx, contrib.GetLogin(), nil)
@@ -16,6 +16,7 @@ package checks
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/go-github/v32/github"
 
@@ -33,11 +34,16 @@ func init() {
 
 // Fuzzing runs Fuzzing check.
 func Fuzzing(c *checker.CheckRequest) checker.CheckResult {
-	url := fmt.Sprintf("github.com/%s/%s", c.Owner, c.Repo)
+	// OSS-Fuzz is on github.com, so it cannot be searched with the client of another host.
+	if !strings.EqualFold(c.Host, "github.com") {
+		return checker.CreateInconclusiveResult(CheckFuzzing,
+			"OSS-Fuzz cannot be searched for repos hosted on GitHub Enterprise Server")
+	}
+	url := fmt.Sprintf("%s/%s/%s", c.Host, c.Owner, c.Repo)
 	searchString := url + " repo:google/oss-fuzz in:file filename:project.yaml"
 	results, _, err := c.Client.Search.Code(c.Ctx, searchString, &github.SearchOptions{})
 	if err != nil {
-		e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Search.Code: %v", err))
+		e := sce.CreateFrom(err, "Client.Search.Code")
 		return checker.CreateRuntimeErrorResult(CheckFuzzing, e)
 	}
 
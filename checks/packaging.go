@@ -15,7 +15,6 @@
 package checks
 
 import (
-	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -42,14 +41,14 @@ func isGithubWorkflowFile(filename string) (bool, error) {
 func Packaging(c *checker.CheckRequest) checker.CheckResult {
 	matchedFiles, err := c.RepoClient.ListFiles(isGithubWorkflowFile)
 	if err != nil {
-		e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("RepoClient.ListFiles: %v", err))
+		e := sce.CreateFrom(err, "RepoClient.ListFiles")
 		return checker.CreateRuntimeErrorResult(CheckPackaging, e)
 	}
 
 	for _, fp := range matchedFiles {
 		fc, err := c.RepoClient.GetFileContent(fp)
 		if err != nil {
-			e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("RepoClient.GetFileContent: %v", err))
+			e := sce.CreateFrom(err, "RepoClient.GetFileContent")
 			return checker.CreateRuntimeErrorResult(CheckPackaging, e)
 		}
 
@@ -62,7 +61,7 @@ func Packaging(c *checker.CheckRequest) checker.CheckResult {
 				Status: "success",
 			})
 		if err != nil {
-			e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Actions.ListWorkflowRunsByFileName: %v", err))
+			e := sce.CreateFrom(err, "Client.Actions.ListWorkflowRunsByFileName")
 			return checker.CreateRuntimeErrorResult(CheckPackaging, e)
 		}
 		if *runs.TotalCount > 0 {
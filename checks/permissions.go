@@ -58,7 +58,7 @@ func validatePermission(key string, value interface{}, path string,
 	val, ok := value.(string)
 	if !ok {
 		//nolint
-		return sce.Create(sce.ErrScorecardInternal, errInvalidGitHubWorkflow.Error())
+		return sce.Create(sce.ErrInvalidRepoContent, errInvalidGitHubWorkflow.Error())
 	}
 
 	if strings.EqualFold(val, "write") {
@@ -85,7 +85,7 @@ func validateMapPermissions(values map[interface{}]interface{}, path string,
 		key, ok := k.(string)
 		if !ok {
 			//nolint
-			return sce.Create(sce.ErrScorecardInternal, errInvalidGitHubWorkflow.Error())
+			return sce.Create(sce.ErrInvalidRepoContent, errInvalidGitHubWorkflow.Error())
 		}
 
 		if err := validatePermission(key, v, path, dl, pPermissions, ignoredPermissions); err != nil {
@@ -132,7 +132,7 @@ func validatePermissions(permissions interface{}, path string,
 	// Invalid type.
 	default:
 		//nolint
-		return sce.Create(sce.ErrScorecardInternal, errInvalidGitHubWorkflow.Error())
+		return sce.Create(sce.ErrInvalidRepoContent, errInvalidGitHubWorkflow.Error())
 	}
 	return nil
 }
@@ -164,14 +164,14 @@ func validateRunLevelPermissions(config map[interface{}]interface{}, path string
 	mjobs, ok := jobs.(map[interface{}]interface{})
 	if !ok {
 		//nolint:wrapcheck
-		return sce.Create(sce.ErrScorecardInternal, errInvalidGitHubWorkflow.Error())
+		return sce.Create(sce.ErrInvalidRepoContent, errInvalidGitHubWorkflow.Error())
 	}
 
 	for _, value := range mjobs {
 		job, ok := value.(map[interface{}]interface{})
 		if !ok {
 			//nolint:wrapcheck
-			return sce.Create(sce.ErrScorecardInternal, errInvalidGitHubWorkflow.Error())
+			return sce.Create(sce.ErrInvalidRepoContent, errInvalidGitHubWorkflow.Error())
 		}
 		// Run-level permissions may be left undefined.
 		// For most workflows, no write permissions are needed,
@@ -209,7 +209,7 @@ func validateDockerfileIsFreeOfInsecureDownloads(pathfn string, content []byte,
 	res, err := parser.Parse(contentReader)
 	if err != nil {
 		//nolint
-		return false, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("%v: %v", errInternalInvalidDockerFile, err))
+		return false, sce.Create(sce.ErrInvalidRepoContent, fmt.Sprintf("%v: %v", errInternalInvalidDockerFile, err))
 	}
 
 	// nolint: prealloc
@@ -230,7 +230,7 @@ func validateDockerfileIsFreeOfInsecureDownloads(pathfn string, content []byte,
 
 		if len(valueList) == 0 {
 			//nolint
-			return false, sce.Create(sce.ErrScorecardInternal, errInternalInvalidDockerFile.Error())
+			return false, sce.Create(sce.ErrInvalidRepoContent, errInternalInvalidDockerFile.Error())
 		}
 
 		// Build a file content.
@@ -295,7 +295,7 @@ func validateDockerfileIsPinned(pathfn string, content []byte,
 	res, err := parser.Parse(contentReader)
 	if err != nil {
 		//nolint
-		return false, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("%v: %v", errInternalInvalidDockerFile, err))
+		return false, sce.Create(sce.ErrInvalidRepoContent, fmt.Sprintf("%v: %v", errInternalInvalidDockerFile, err))
 	}
 
 	for _, child := range res.AST.Children {
@@ -343,7 +343,7 @@ func validateDockerfileIsPinned(pathfn string, content []byte,
 		default:
 			// That should not happen.
 			//nolint
-			return false, sce.Create(sce.ErrScorecardInternal, errInternalInvalidDockerFile.Error())
+			return false, sce.Create(sce.ErrInvalidRepoContent, errInternalInvalidDockerFile.Error())
 		}
 	}
 
@@ -389,7 +389,7 @@ func validateGitHubWorkflowIsFreeOfInsecureDownloads(pathfn string, content []by
 	err := yaml.Unmarshal(content, &workflow)
 	if err != nil {
 		//nolint
-		return false, sce.Create(sce.ErrScorecardInternal,
+		return false, sce.Create(sce.ErrInvalidRepoContent,
 			fmt.Sprintf("%v: %v", errInternalInvalidYamlFile, err))
 	}
 
@@ -471,7 +471,7 @@ func validateGitHubActionWorkflow(pathfn string, content []byte,
 	err := yaml.Unmarshal(content, &workflow)
 	if err != nil {
 		//nolint
-		return false, sce.Create(sce.ErrScorecardInternal,
+		return false, sce.Create(sce.ErrInvalidRepoContent,
 			fmt.Sprintf("%v: %v", errInternalInvalidYamlFile, err))
 	}
 
@@ -110,7 +110,7 @@ func sastToolInCheckRuns(c *checker.CheckRequest) (int, error) {
 	if err != nil {
 		//nolint
 		return checker.InconclusiveResultScore,
-			sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.PullRequests.List: %v", err))
+			sce.CreateFrom(err, "Client.PullRequests.List")
 	}
 
 	totalMerged := 0
@@ -124,7 +124,7 @@ func sastToolInCheckRuns(c *checker.CheckRequest) (int, error) {
 			&github.ListCheckRunsOptions{})
 		if err != nil {
 			return checker.InconclusiveResultScore,
-				sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Checks.ListCheckRunsForRef: %v", err))
+				sce.CreateFrom(err, "Client.Checks.ListCheckRunsForRef")
 		}
 		if crs == nil {
 			c.Dlogger.Warn("no pull requests merged into dev branch")
@@ -164,7 +164,7 @@ func codeQLInCheckDefinitions(c *checker.CheckRequest) (int, error) {
 	results, _, err := c.Client.Search.Code(c.Ctx, searchQuery, &github.SearchOptions{})
 	if err != nil {
 		return checker.InconclusiveResultScore,
-			sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Search.Code: %v", err))
+			sce.CreateFrom(err, "Client.Search.Code")
 	}
 
 	for _, result := range results.CodeResults {
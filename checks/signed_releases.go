@@ -39,7 +39,7 @@ func init() {
 func SignedReleases(c *checker.CheckRequest) checker.CheckResult {
 	releases, _, err := c.Client.Repositories.ListReleases(c.Ctx, c.Owner, c.Repo, &github.ListOptions{})
 	if err != nil {
-		e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Repositories.ListReleases: %v", err))
+		e := sce.CreateFrom(err, "Client.Repositories.ListReleases")
 		return checker.CreateRuntimeErrorResult(CheckSignedReleases, e)
 	}
 
@@ -50,7 +50,7 @@ func SignedReleases(c *checker.CheckRequest) checker.CheckResult {
 	for _, r := range releases {
 		assets, _, err := c.Client.Repositories.ListReleaseAssets(c.Ctx, c.Owner, c.Repo, r.GetID(), &github.ListOptions{})
 		if err != nil {
-			e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("Client.Repositories.ListReleaseAssets: %v", err))
+			e := sce.CreateFrom(err, "Client.Repositories.ListReleaseAssets")
 			return checker.CreateRuntimeErrorResult(CheckSignedReleases, e)
 		}
 		if len(assets) == 0 {
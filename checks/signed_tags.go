@@ -57,7 +57,7 @@ func SignedTags(c *checker.CheckRequest) checker.CheckResult {
 	}
 
 	if err := c.GraphClient.Query(c.Ctx, &query, variables); err != nil {
-		e := sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("GraphClient.Query: %v", err))
+		e := sce.CreateFrom(err, "GraphClient.Query")
 		return checker.CreateRuntimeErrorResult(CheckSignedTags, e)
 	}
 	totalTags := 0
@@ -0,0 +1,280 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation"
+	"github.com/google/go-github/v32/github"
+	"golang.org/x/sync/singleflight"
+)
+
+// notInstalledTTL is how long an owner without an installation of the app is remembered,
+// so that installing the app takes effect without restarting long-running processes.
+const notInstalledTTL = time.Hour
+
+// Matches the owner in search qualifiers, e.g. `repo:owner/name` or `org:owner`.
+var searchOwner = regexp.MustCompile(`(?:^|\s)(?:repo:([^/\s]+)/|org:(\S+)|user:(\S+))`)
+
+// MakeGitHubAppTransport returns a RoundTripper which authenticates as an installation of
+// the GitHub App for the owner of each requested repository. Installations are looked up
+// with the app's JWT on first use of each owner and cached. Requests for owners where the
+// app is not installed, or whose owner cannot be determined, are sent using fallback,
+// e.g. a transport created with MakeGitHubTransport. Installations on registered GitHub
+// Enterprise Server hosts are looked up with the API of the host, and other requests use
+// the API at appsTransport.BaseURL.
+func MakeGitHubAppTransport(appsTransport *ghinstallation.AppsTransport,
+	fallback http.RoundTripper) (http.RoundTripper, error) {
+	app, err := newHostApp(appsTransport)
+	if err != nil {
+		return nil, err
+	}
+	return &appTransport{
+		app:           app,
+		hostApps:      make(map[string]*hostApp),
+		fallback:      fallback,
+		installations: make(map[string]installation),
+		now:           time.Now,
+	}, nil
+}
+
+// hostApp authenticates as the GitHub App with the API of a host.
+type hostApp struct {
+	appsTransport *ghinstallation.AppsTransport
+	client        *github.Client
+}
+
+func newHostApp(appsTransport *ghinstallation.AppsTransport) (*hostApp, error) {
+	client := github.NewClient(&http.Client{Transport: appsTransport})
+	if appsTransport.BaseURL != "" {
+		baseURL, err := url.Parse(strings.TrimSuffix(appsTransport.BaseURL, "/") + "/")
+		if err != nil {
+			return nil, fmt.Errorf("error parsing GitHub App base URL: %w", err)
+		}
+		client.BaseURL = baseURL
+	}
+	return &hostApp{appsTransport: appsTransport, client: client}, nil
+}
+
+// installation is a cached lookup of the app installation for an owner.
+// transport is nil if the app is not installed for the owner.
+type installation struct {
+	transport http.RoundTripper
+	checked   time.Time
+}
+
+// appTransport handles authorization using per-owner GitHub App installations during HTTP requests.
+type appTransport struct {
+	// app is used for requests to hosts other than GitHub Enterprise Server hosts.
+	app *hostApp
+	// hostApps maps the API URL of GitHub Enterprise Server hosts to their hostApp.
+	hostApps map[string]*hostApp
+	fallback http.RoundTripper
+	now      func() time.Time
+	// installations maps `<API URL> <lowercase owner>` to the owner's installation.
+	installations map[string]installation
+	// lookups coalesces concurrent lookups of the same owner.
+	lookups singleflight.Group
+	// mu guards hostApps and installations. It is not held during lookups, so that
+	// they do not block requests for other owners.
+	mu sync.RWMutex
+}
+
+func (at *appTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	owner, err := requestOwner(r)
+	if err != nil {
+		return nil, err
+	}
+	transport := at.fallback
+	if owner != "" {
+		installed, err := at.installationFor(r, owner)
+		if err != nil {
+			return nil, err
+		}
+		if installed != nil {
+			transport = installed
+		}
+	}
+	resp, err := transport.RoundTrip(r)
+	if err != nil {
+		return nil, fmt.Errorf("error in HTTP: %w", err)
+	}
+	return resp, nil
+}
+
+// appFor returns the hostApp for the host a request is sent to.
+func (at *appTransport) appFor(r *http.Request) (*hostApp, error) {
+	apiURL, ok := enterpriseAPIURL(r.URL.Host)
+	if !ok {
+		return at.app, nil
+	}
+	at.mu.RLock()
+	app := at.hostApps[apiURL]
+	at.mu.RUnlock()
+	if app != nil {
+		return app, nil
+	}
+	appsTransport := *at.app.appsTransport
+	appsTransport.BaseURL = apiURL
+	app, err := newHostApp(&appsTransport)
+	if err != nil {
+		return nil, err
+	}
+	at.mu.Lock()
+	defer at.mu.Unlock()
+	if existing := at.hostApps[apiURL]; existing != nil {
+		return existing, nil
+	}
+	at.hostApps[apiURL] = app
+	return app, nil
+}
+
+// installationFor returns the installation transport for owner, or nil if the app is not
+// installed for owner.
+func (at *appTransport) installationFor(r *http.Request, owner string) (http.RoundTripper, error) {
+	app, err := at.appFor(r)
+	if err != nil {
+		return nil, err
+	}
+	key := app.appsTransport.BaseURL + " " + strings.ToLower(owner)
+	if transport, ok := at.cachedInstallation(key); ok {
+		return transport, nil
+	}
+	v, err, _ := at.lookups.Do(key, func() (interface{}, error) {
+		// A concurrent lookup may have finished since the cache was checked.
+		if transport, ok := at.cachedInstallation(key); ok {
+			return transport, nil
+		}
+		id, err := app.lookupInstallation(r, owner)
+		if err != nil {
+			return nil, err
+		}
+		var transport http.RoundTripper
+		if id != 0 {
+			transport = ghinstallation.NewFromAppsTransport(app.appsTransport, id)
+		}
+		at.mu.Lock()
+		at.installations[key] = installation{transport: transport, checked: at.now()}
+		at.mu.Unlock()
+		return transport, nil
+	})
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, err
+	}
+	transport, _ := v.(http.RoundTripper)
+	return transport, nil
+}
+
+// cachedInstallation returns the cached installation transport for key, and whether
+// the lookup of key is cached and has not expired.
+func (at *appTransport) cachedInstallation(key string) (http.RoundTripper, bool) {
+	at.mu.RLock()
+	defer at.mu.RUnlock()
+	cached, ok := at.installations[key]
+	if !ok || (cached.transport == nil && at.now().Sub(cached.checked) >= notInstalledTTL) {
+		return nil, false
+	}
+	return cached.transport, true
+}
+
+// lookupInstallation returns the ID of the installation of the app for owner, which may be
+// either an organization or a user, or 0 if the app is not installed for owner.
+func (app *hostApp) lookupInstallation(r *http.Request, owner string) (int64, error) {
+	inst, resp, err := app.client.Apps.FindOrganizationInstallation(r.Context(), owner)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		inst, resp, err = app.client.Apps.FindUserInstallation(r.Context(), owner)
+	}
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error finding GitHub App installation for %s: %w", owner, err)
+	}
+	return inst.GetID(), nil
+}
+
+// requestOwner returns the owner of the repository a request to the GitHub API is for, or
+// an empty string if it cannot be determined.
+func requestOwner(r *http.Request) (string, error) {
+	segments := strings.FieldsFunc(r.URL.Path, func(r rune) bool { return r == '/' })
+	// Skip the path prefix of GitHub Enterprise Server, e.g. `/api/v3`.
+	for len(segments) > 0 && (segments[0] == "api" || segments[0] == "v3") {
+		segments = segments[1:]
+	}
+	if len(segments) == 0 {
+		return "", nil
+	}
+
+	switch segments[0] {
+	case "repos", "orgs", "users":
+		if len(segments) > 1 {
+			return segments[1], nil
+		}
+	case "search":
+		if m := searchOwner.FindStringSubmatch(r.URL.Query().Get("q")); m != nil {
+			return m[1] + m[2] + m[3], nil
+		}
+	case "graphql":
+		return graphQLOwner(r)
+	}
+	return "", nil
+}
+
+// graphQLOwner returns the `owner` variable of a GraphQL request, without consuming its body.
+func graphQLOwner(r *http.Request) (string, error) {
+	if r.Body == nil {
+		return "", nil
+	}
+	var body []byte
+	var err error
+	if r.GetBody != nil {
+		rc, err := r.GetBody()
+		if err != nil {
+			return "", fmt.Errorf("error rewinding request body: %w", err)
+		}
+		defer rc.Close()
+		body, err = ioutil.ReadAll(rc)
+		if err != nil {
+			return "", fmt.Errorf("error reading request body: %w", err)
+		}
+	} else {
+		body, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			return "", fmt.Errorf("error reading request body: %w", err)
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	var query struct {
+		Variables struct {
+			Owner string `json:"owner"`
+		} `json:"variables"`
+	}
+	// Requests which are not valid GraphQL are left to fail upstream.
+	// nolint: errcheck
+	_ = json.Unmarshal(body, &query)
+	return query.Variables.Owner, nil
+}
@@ -0,0 +1,289 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bradleyfalzon/ghinstallation"
+)
+
+// fakeAppServer serves installation lookups and tokens for a GitHub App, and records the
+// Authorization header of all other requests.
+type fakeAppServer struct {
+	orgs    map[string]int64
+	users   map[string]int64
+	lookups map[string]int
+	mu      sync.Mutex
+}
+
+func (s *fakeAppServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// GitHub Enterprise Server serves the API under /api/v3.
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v3"), "/"), "/")
+	switch {
+	case len(segments) == 3 && segments[2] == "installation":
+		s.lookups[segments[1]]++
+		ids := s.orgs
+		if segments[0] == "users" {
+			ids = s.users
+		}
+		if id, ok := ids[segments[1]]; ok {
+			fmt.Fprintf(w, `{"id": %d}`, id)
+			return
+		}
+		http.NotFound(w, r)
+	case len(segments) == 4 && segments[0] == "app" && segments[3] == "access_tokens":
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "installation-%s", "expires_at": "2099-01-01T00:00:00Z"}`, segments[2])
+	default:
+		fmt.Fprint(w, r.Header.Get("Authorization"))
+	}
+}
+
+func TestGitHubAppTransport(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	fake := &fakeAppServer{
+		orgs:    map[string]int64{"installed-org": 1},
+		users:   map[string]int64{"installed-user": 2},
+		lookups: make(map[string]int),
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	appsTransport := ghinstallation.NewAppsTransportFromPrivateKey(http.DefaultTransport, 1, key)
+	appsTransport.BaseURL = server.URL
+	transport, err := MakeGitHubAppTransport(appsTransport,
+		MakeGitHubTransport(http.DefaultTransport, []string{"pat"}))
+	if err != nil {
+		t.Fatalf("MakeGitHubAppTransport: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   string
+	}{
+		{
+			name:   "org installation",
+			method: http.MethodGet,
+			path:   "/repos/installed-org/repo/commits",
+			want:   "token installation-1",
+		},
+		{
+			name:   "cached org installation",
+			method: http.MethodGet,
+			path:   "/repos/Installed-Org/repo/releases",
+			want:   "token installation-1",
+		},
+		{
+			name:   "user installation",
+			method: http.MethodGet,
+			path:   "/repos/installed-user/repo",
+			want:   "token installation-2",
+		},
+		{
+			name:   "not installed",
+			method: http.MethodGet,
+			path:   "/repos/other/repo",
+			want:   "Bearer pat",
+		},
+		{
+			name:   "unknown owner",
+			method: http.MethodGet,
+			path:   "/rate_limit",
+			want:   "Bearer pat",
+		},
+		{
+			name:   "search",
+			method: http.MethodGet,
+			path:   "/search/code?q=codeql+repo:installed-user/repo",
+			want:   "token installation-2",
+		},
+		{
+			name:   "graphql",
+			method: http.MethodPost,
+			path:   "/graphql",
+			body:   `{"query": "{}", "variables": {"owner": "installed-org", "name": "repo"}}`,
+			want:   "token installation-1",
+		},
+	}
+	// Subtests share the installation cache, so they run sequentially.
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatalf("%s: http.NewRequest: %v", tt.name, err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s: client.Do: %v", tt.name, err)
+		}
+		got, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s: ioutil.ReadAll: %v", tt.name, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: Authorization = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for owner, lookups := range fake.lookups {
+		// Owners which are not organizations are looked up twice, as an org and as a user.
+		if max := 2; lookups > max {
+			t.Errorf("installation for %s looked up %d times", owner, lookups)
+		}
+	}
+}
+
+func TestGitHubAppTransportConcurrentLookups(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	fake := &fakeAppServer{
+		orgs:    map[string]int64{"installed-org": 1, "slow-org": 3},
+		lookups: make(map[string]int),
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The lookup of slow-org blocks until released.
+		if r.URL.Path == "/orgs/slow-org/installation" {
+			once.Do(func() { close(started) })
+			<-release
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	appsTransport := ghinstallation.NewAppsTransportFromPrivateKey(http.DefaultTransport, 1, key)
+	appsTransport.BaseURL = server.URL
+	transport, err := MakeGitHubAppTransport(appsTransport,
+		MakeGitHubTransport(http.DefaultTransport, []string{"pat"}))
+	if err != nil {
+		t.Fatalf("MakeGitHubAppTransport: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+	get := func(path string) (string, error) {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		got, err := ioutil.ReadAll(resp.Body)
+		return string(got), err
+	}
+	if _, err := get("/repos/installed-org/repo"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	const slowRequests = 3
+	var wg sync.WaitGroup
+	for i := 0; i < slowRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := get("/repos/slow-org/repo"); err != nil || got != "token installation-3" {
+				t.Errorf("slow-org: Authorization = %q, err = %v", got, err)
+			}
+		}()
+	}
+	<-started
+	// Requests for cached owners are not blocked by the pending lookup.
+	if got, err := get("/repos/installed-org/repo"); err != nil || got != "token installation-1" {
+		t.Errorf("installed-org: Authorization = %q, err = %v", got, err)
+	}
+	close(release)
+	wg.Wait()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if lookups := fake.lookups["slow-org"]; lookups != 1 {
+		t.Errorf("installation for slow-org looked up %d times", lookups)
+	}
+}
+
+func TestGitHubAppTransportEnterpriseHost(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	githubServer := httptest.NewServer(&fakeAppServer{lookups: make(map[string]int)})
+	t.Cleanup(githubServer.Close)
+	enterprise := httptest.NewServer(&fakeAppServer{
+		orgs:    map[string]int64{"enterprise-org": 4},
+		lookups: make(map[string]int),
+	})
+	t.Cleanup(enterprise.Close)
+	if err := RegisterHost(Host{Name: "app-transport.example.com", APIURL: enterprise.URL + "/api/v3/"}); err != nil {
+		t.Fatalf("RegisterHost: %v", err)
+	}
+
+	appsTransport := ghinstallation.NewAppsTransportFromPrivateKey(http.DefaultTransport, 1, key)
+	appsTransport.BaseURL = githubServer.URL
+	transport, err := MakeGitHubAppTransport(appsTransport,
+		MakeGitHubTransport(http.DefaultTransport, []string{"pat"}))
+	if err != nil {
+		t.Fatalf("MakeGitHubAppTransport: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "enterprise installation", url: enterprise.URL + "/api/v3/repos/enterprise-org/repo",
+			want: "token installation-4"},
+		// The owner is looked up on each host separately.
+		{name: "not installed on github.com", url: githubServer.URL + "/repos/enterprise-org/repo",
+			want: "Bearer pat"},
+	}
+	for _, tt := range tests {
+		resp, err := client.Get(tt.url)
+		if err != nil {
+			t.Fatalf("%s: client.Get: %v", tt.name, err)
+		}
+		got, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s: ioutil.ReadAll: %v", tt.name, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: Authorization = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/shurcooL/githubv4"
 
 	"github.com/ossf/scorecard/v2/clients"
+	sce "github.com/ossf/scorecard/v2/errors"
 )
 
 // Client is GitHub-specific implementation of RepoClient.
@@ -40,12 +41,12 @@ func (client *Client) InitRepo(owner, repoName string) error {
 	repo, _, err := client.repoClient.Repositories.Get(client.ctx, owner, repoName)
 	if err != nil {
 		// nolint: wrapcheck
-		return clients.NewRepoUnavailableError(err)
+		return clients.NewRepoUnavailableError(sce.CreateFrom(err, "Repositories.Get"))
 	}
 	client.repo = repo
 
 	// Init tarballHandler.
-	if err := client.tarball.init(client.ctx, client.repo); err != nil {
+	if err := client.tarball.init(client.ctx, client.repoClient, client.repo); err != nil {
 		return fmt.Errorf("error during tarballHandler.init: %w", err)
 	}
 
@@ -93,6 +94,8 @@ func (client *Client) Close() error {
 }
 
 // CreateGithubRepoClient returns a Client which implements RepoClient interface.
+// graphClient may be nil when the GraphQL API is unavailable, in which case the
+// methods backed by it return ErrGraphQLUnavailable.
 func CreateGithubRepoClient(ctx context.Context,
 	client *github.Client, graphClient *githubv4.Client) clients.RepoClient {
 	return &Client{
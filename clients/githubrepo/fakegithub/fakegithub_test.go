@@ -0,0 +1,97 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakegithub_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/checks"
+	"github.com/ossf/scorecard/v2/clients/githubrepo"
+	"github.com/ossf/scorecard/v2/clients/githubrepo/fakegithub"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+// TestRunScorecards runs all checks which only depend on GitHub against the fake server.
+func TestRunScorecards(t *testing.T) {
+	t.Parallel()
+	fixtures, err := fakegithub.LoadFixtures("testdata/fixtures.yaml")
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	server := fakegithub.NewServer(fixtures)
+	t.Cleanup(server.Close)
+	if err := githubrepo.SetGitHubAPIURL(server.URL); err != nil {
+		t.Fatalf("SetGitHubAPIURL: %v", err)
+	}
+
+	httpClient := &http.Client{}
+	githubClient, graphClient, err := githubrepo.NewClients(httpClient, "github.com")
+	if err != nil {
+		t.Fatalf("NewClients: %v", err)
+	}
+	repoURL := repos.RepoURL{}
+	if err := repoURL.Set("github.com/ossf-tests/scorecard-fake"); err != nil {
+		t.Fatalf("RepoURL.Set: %v", err)
+	}
+	repoClient := githubrepo.CreateGithubRepoClient(context.Background(), githubClient, graphClient)
+	defer repoClient.Close()
+
+	// These checks call services other than GitHub.
+	enabledChecks := checker.CheckNameToFnMap{}
+	for name, fn := range checks.AllChecks {
+		if name != checks.CheckCIIBestPractices && name != checks.CheckVulnerabilities {
+			enabledChecks[name] = fn
+		}
+	}
+	result, err := pkg.RunScorecards(context.Background(), repoURL, enabledChecks,
+		repoClient, httpClient, githubClient, graphClient)
+	if err != nil {
+		t.Fatalf("RunScorecards: %v", err)
+	}
+
+	scores := map[string]int{}
+	for _, check := range result.Checks {
+		if check.Error2 != nil {
+			t.Errorf("%s: unexpected error: %v", check.Name, check.Error2)
+		}
+		scores[check.Name] = check.Score
+	}
+	expected := map[string]int{
+		checks.CheckActive:                    2,
+		checks.CheckAutomaticDependencyUpdate: checker.MaxResultScore,
+		checks.CheckBinaryArtifacts:           checker.MaxResultScore,
+		checks.CheckBranchProtection:          9,
+		checks.CheckCITests:                   checker.MaxResultScore,
+		checks.CheckCodeReview:                checker.MaxResultScore,
+		checks.CheckContributors:              checker.MaxResultScore,
+		checks.CheckFuzzing:                   checker.MaxResultScore,
+		checks.CheckPackaging:                 checker.MaxResultScore,
+		checks.CheckPinnedDependencies:        5,
+		checks.CheckSAST:                      checker.MaxResultScore,
+		checks.CheckSecurityPolicy:            checker.MaxResultScore,
+		checks.CheckSignedReleases:            checker.MaxResultScore,
+		checks.CheckSignedTags:                checker.MaxResultScore,
+		checks.CheckTokenPermissions:          checker.MaxResultScore,
+	}
+	if diff := cmp.Diff(expected, scores); diff != "" {
+		t.Errorf("unexpected scores (-want +got):\n%s", diff)
+	}
+}
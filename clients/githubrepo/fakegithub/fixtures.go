@@ -0,0 +1,191 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakegithub implements a fake GitHub API server, backed by fixtures, for
+// testing scorecard end to end without access to GitHub.
+package fakegithub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+// Fixtures is the content served by the fake GitHub server.
+type Fixtures struct {
+	Repos []Repo `yaml:"repos"`
+	Users []User `yaml:"users"`
+}
+
+// Repo is a repository, along with everything the fake GitHub server returns about it.
+type Repo struct {
+	// Files maps paths to their content, served in the repository's tarball and searched
+	// by code search.
+	Files map[string]string `yaml:"files"`
+	// CheckRuns and Statuses map commit SHAs to the check runs and statuses of the commit.
+	CheckRuns map[string][]CheckRun `yaml:"check_runs"`
+	Statuses  map[string][]Status   `yaml:"statuses"`
+	// BranchProtection maps protected branch names to their protection.
+	BranchProtection map[string]BranchProtection `yaml:"branch_protection"`
+	// WorkflowRuns maps workflow file names, e.g. `publish.yml`, to their runs.
+	WorkflowRuns  map[string][]WorkflowRun `yaml:"workflow_runs"`
+	Owner         string                   `yaml:"owner"`
+	Name          string                   `yaml:"name"`
+	DefaultBranch string                   `yaml:"default_branch"`
+	Branches      []string                 `yaml:"branches"`
+	Commits       []Commit                 `yaml:"commits"`
+	PullRequests  []PullRequest            `yaml:"pull_requests"`
+	Releases      []Release                `yaml:"releases"`
+	Tags          []Tag                    `yaml:"tags"`
+	Contributors  []Contributor            `yaml:"contributors"`
+	Archived      bool                     `yaml:"archived"`
+	Fork          bool                     `yaml:"fork"`
+}
+
+// FullName returns the `owner/name` of the repo.
+func (r *Repo) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// Commit is a commit on the default branch, most recent first.
+type Commit struct {
+	SHA       string `yaml:"sha"`
+	Message   string `yaml:"message"`
+	Committer string `yaml:"committer"`
+	// Age is how long before the server's current time the commit was made, e.g. `48h`,
+	// so that the Active check gets the same result whenever it runs.
+	Age time.Duration `yaml:"age"`
+}
+
+// PullRequest is a pull request. It is merged if MergedAt is set.
+type PullRequest struct {
+	MergedAt *time.Time `yaml:"merged_at"`
+	// State defaults to `closed` for merged pull requests and `open` otherwise.
+	State   string   `yaml:"state"`
+	HeadSHA string   `yaml:"head_sha"`
+	Labels  []string `yaml:"labels"`
+	// Reviews are the states of the latest reviews, e.g. `APPROVED`.
+	Reviews             []string `yaml:"reviews"`
+	Number              int      `yaml:"number"`
+	AuthoredByCommitter bool     `yaml:"authored_by_committer"`
+}
+
+func (pr *PullRequest) state() string {
+	if pr.State != "" {
+		return pr.State
+	}
+	if pr.MergedAt != nil {
+		return "closed"
+	}
+	return "open"
+}
+
+// CheckRun is a check run of the GitHub App App, e.g. `github-actions`.
+type CheckRun struct {
+	App        string `yaml:"app"`
+	Status     string `yaml:"status"`
+	Conclusion string `yaml:"conclusion"`
+}
+
+// Status is a commit status.
+type Status struct {
+	Context string `yaml:"context"`
+	State   string `yaml:"state"`
+}
+
+// BranchProtection is the protection of a branch.
+type BranchProtection struct {
+	RequiredStatusChecks     []string `yaml:"required_status_checks"`
+	RequiredApprovingReviews int      `yaml:"required_approving_reviews"`
+	StrictStatusChecks       bool     `yaml:"strict_status_checks"`
+	DismissStaleReviews      bool     `yaml:"dismiss_stale_reviews"`
+	RequireCodeOwnerReviews  bool     `yaml:"require_code_owner_reviews"`
+	EnforceAdmins            bool     `yaml:"enforce_admins"`
+	RequireLinearHistory     bool     `yaml:"require_linear_history"`
+	AllowForcePushes         bool     `yaml:"allow_force_pushes"`
+	AllowDeletions           bool     `yaml:"allow_deletions"`
+}
+
+// WorkflowRun is a run of a GitHub Actions workflow.
+type WorkflowRun struct {
+	Status     string `yaml:"status"`
+	Conclusion string `yaml:"conclusion"`
+}
+
+// Release is a GitHub release.
+type Release struct {
+	TagName string `yaml:"tag_name"`
+	// TargetCommitish defaults to the default branch of the repo.
+	TargetCommitish string   `yaml:"target_commitish"`
+	Assets          []string `yaml:"assets"`
+	ID              int64    `yaml:"id"`
+}
+
+// Tag is an annotated tag.
+type Tag struct {
+	Name string `yaml:"name"`
+	// SHA is the SHA of the tag object.
+	SHA      string `yaml:"sha"`
+	Verified bool   `yaml:"verified"`
+}
+
+// Contributor is a contributor to a repo.
+type Contributor struct {
+	Login         string `yaml:"login"`
+	Contributions int    `yaml:"contributions"`
+}
+
+// User is a GitHub user.
+type User struct {
+	Login   string   `yaml:"login"`
+	Company string   `yaml:"company"`
+	Orgs    []string `yaml:"orgs"`
+}
+
+// LoadFixtures reads Fixtures from a YAML file.
+func LoadFixtures(filename string) (*Fixtures, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixtures: %w", err)
+	}
+	var fixtures Fixtures
+	if err := yaml.UnmarshalStrict(content, &fixtures); err != nil {
+		//nolint:wrapcheck
+		return nil, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("yaml.UnmarshalStrict: %v", err))
+	}
+	return &fixtures, nil
+}
+
+func (f *Fixtures) repo(owner, name string) *Repo {
+	for i := range f.Repos {
+		if strings.EqualFold(f.Repos[i].Owner, owner) && strings.EqualFold(f.Repos[i].Name, name) {
+			return &f.Repos[i]
+		}
+	}
+	return nil
+}
+
+func (f *Fixtures) user(login string) *User {
+	for i := range f.Users {
+		if strings.EqualFold(f.Users[i].Login, login) {
+			return &f.Users[i]
+		}
+	}
+	return nil
+}
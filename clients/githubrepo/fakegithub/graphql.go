@@ -0,0 +1,150 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakegithub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type graphQLRequest struct {
+	Variables map[string]interface{} `json:"variables"`
+	Query     string                 `json:"query"`
+}
+
+// object is a GraphQL response object.
+type object map[string]interface{}
+
+// serveGraphQL answers the GraphQL queries made by scorecard. Instead of interpreting the
+// query, it recognizes which of scorecard's queries it is and returns all of its fields.
+func (h *handler) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Problems parsing JSON: %v", err))
+		return
+	}
+
+	owner, _ := req.Variables["owner"].(string)
+	name, _ := req.Variables["name"].(string)
+	repo := h.fixtures.repo(owner, name)
+	if repo == nil {
+		writeJSON(w, object{
+			"data": object{"repository": nil},
+			"errors": []object{{
+				"type":    "NOT_FOUND",
+				"path":    []string{"repository"},
+				"message": fmt.Sprintf("Could not resolve to a Repository with the name '%s/%s'.", owner, name),
+			}},
+		})
+		return
+	}
+
+	var repository object
+	if strings.Contains(req.Query, "refs(") {
+		repository = refs(repo, intVariable(req.Variables, "count"))
+	} else {
+		repository = repositoryData(repo, req.Variables)
+	}
+	writeJSON(w, object{"data": object{"repository": repository}})
+}
+
+// refs returns the last count tags of repo, as queried by the Signed-Tags check.
+func refs(repo *Repo, count int) object {
+	tags := repo.Tags
+	if count > 0 && len(tags) > count {
+		tags = tags[len(tags)-count:]
+	}
+	nodes := []object{}
+	for _, tag := range tags {
+		nodes = append(nodes, object{
+			"name":   tag.Name,
+			"target": object{"oid": tag.SHA},
+		})
+	}
+	return object{"refs": object{"nodes": nodes}}
+}
+
+// repositoryData returns the data of repo as queried by githubrepo.Client.
+func repositoryData(repo *Repo, variables map[string]interface{}) object {
+	commits := repo.Commits
+	if n := intVariable(variables, "commitsToAnalyze"); n > 0 && len(commits) > n {
+		commits = commits[:n]
+	}
+	now := time.Now().UTC()
+	history := []object{}
+	for _, commit := range commits {
+		history = append(history, object{
+			"committedDate": now.Add(-commit.Age).Format(time.RFC3339),
+			"message":       commit.Message,
+			"oid":           commit.SHA,
+			"committer":     object{"user": object{"login": commit.Committer}},
+		})
+	}
+
+	var merged []*PullRequest
+	for i := range repo.PullRequests {
+		if repo.PullRequests[i].MergedAt != nil {
+			merged = append(merged, &repo.PullRequests[i])
+		}
+	}
+	if n := intVariable(variables, "pullRequestsToAnalyze"); n > 0 && len(merged) > n {
+		merged = merged[len(merged)-n:]
+	}
+	prs := []object{}
+	for _, pr := range merged {
+		labels := []object{}
+		for _, label := range pr.Labels {
+			labels = append(labels, object{"name": label})
+		}
+		reviews := []object{}
+		for _, state := range pr.Reviews {
+			reviews = append(reviews, object{"state": state})
+		}
+		prs = append(prs, object{
+			"number":        pr.Number,
+			"mergeCommit":   object{"authoredByCommitter": pr.AuthoredByCommitter},
+			"mergedAt":      pr.MergedAt.Format(time.RFC3339),
+			"labels":        object{"nodes": labels},
+			"latestReviews": object{"nodes": reviews},
+		})
+	}
+
+	return object{
+		"isArchived": repo.Archived,
+		"defaultBranchRef": object{
+			"name": repo.DefaultBranch,
+			"branchProtectionRule": object{
+				"requiredApprovingReviewCount": repo.BranchProtection[repo.DefaultBranch].RequiredApprovingReviews,
+			},
+			"target": object{"history": object{"nodes": history}},
+		},
+		"pullRequests": object{"nodes": prs},
+	}
+}
+
+func intVariable(variables map[string]interface{}, name string) int {
+	// JSON numbers are decoded as float64.
+	if n, ok := variables[name].(float64); ok {
+		return int(n)
+	}
+	return 0
+}
@@ -0,0 +1,513 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakegithub
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+)
+
+// rateLimit is the rate limit reported for all resources.
+const rateLimit = 5000
+
+// Matches search qualifiers such as `repo:owner/name`, and quoted or plain search terms.
+var searchTerm = regexp.MustCompile(`(?:(\w+):)?(?:"([^"]*)"|(\S+))`)
+
+// NewServer starts a fake GitHub server serving fixtures. The REST API is served at its
+// root, and the GraphQL API at `/graphql`. The caller must Close the server.
+func NewServer(fixtures *Fixtures) *httptest.Server {
+	return httptest.NewServer(NewHandler(fixtures))
+}
+
+// NewHandler returns the http.Handler of a fake GitHub server serving fixtures. It serves
+// the subset of the GitHub REST and GraphQL APIs used by scorecard, with or without the
+// `/api/v3` path prefix of GitHub Enterprise Server.
+func NewHandler(fixtures *Fixtures) http.Handler {
+	return &handler{fixtures: fixtures}
+}
+
+type handler struct {
+	fixtures *Fixtures
+}
+
+// nolint: gocyclo
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.FieldsFunc(r.URL.Path, func(r rune) bool { return r == '/' })
+	if len(segments) >= 2 && segments[0] == "api" && segments[1] == "v3" {
+		segments = segments[2:]
+	}
+	if len(segments) == 0 {
+		notFound(w)
+		return
+	}
+
+	switch segments[0] {
+	case "graphql":
+		h.serveGraphQL(w, r)
+	case "rate_limit":
+		serveRateLimit(w)
+	case "search":
+		if len(segments) == 2 && segments[1] == "code" {
+			h.serveCodeSearch(w, r)
+			return
+		}
+		notFound(w)
+	case "users":
+		h.serveUser(w, segments[1:])
+	case "orgs":
+		if len(segments) == 3 && segments[2] == "repos" {
+			h.serveOrgRepos(w, r, segments[1])
+			return
+		}
+		notFound(w)
+	case "repos":
+		if len(segments) < 3 {
+			notFound(w)
+			return
+		}
+		repo := h.fixtures.repo(segments[1], segments[2])
+		if repo == nil {
+			notFound(w)
+			return
+		}
+		h.serveRepo(w, r, repo, segments[3:])
+	default:
+		notFound(w)
+	}
+}
+
+// nolint: gocyclo
+func (h *handler) serveRepo(w http.ResponseWriter, r *http.Request, repo *Repo, segments []string) {
+	if len(segments) == 0 {
+		writeJSON(w, repository(r, repo))
+		return
+	}
+	switch {
+	case segments[0] == "tarball":
+		serveTarball(w, repo)
+	case len(segments) == 1 && segments[0] == "pulls":
+		writeJSON(w, pullRequests(repo, r.URL.Query().Get("state")))
+	case len(segments) == 3 && segments[0] == "commits" && segments[2] == "check-runs":
+		writeJSON(w, checkRuns(repo, segments[1]))
+	case len(segments) == 3 && segments[0] == "commits" && segments[2] == "statuses":
+		writeJSON(w, statuses(repo, segments[1]))
+	case len(segments) == 1 && segments[0] == "releases":
+		writeJSON(w, releases(repo))
+	case len(segments) == 3 && segments[0] == "releases" && segments[2] == "assets":
+		assets, ok := releaseAssets(repo, segments[1])
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, assets)
+	case len(segments) == 3 && segments[0] == "git" && segments[1] == "tags":
+		tag, ok := gitTag(repo, segments[2])
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, tag)
+	case len(segments) == 1 && segments[0] == "branches":
+		writeJSON(w, branches(repo))
+	case len(segments) > 2 && segments[0] == "branches" && segments[len(segments)-1] == "protection":
+		// Branch names may contain slashes.
+		protection, ok := repo.BranchProtection[strings.Join(segments[1:len(segments)-1], "/")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Branch not protected")
+			return
+		}
+		writeJSON(w, branchProtection(&protection))
+	case len(segments) == 1 && segments[0] == "contributors":
+		writeJSON(w, contributors(repo))
+	case len(segments) == 4 && segments[0] == "actions" && segments[1] == "workflows" && segments[3] == "runs":
+		writeJSON(w, workflowRuns(repo, segments[2], r.URL.Query().Get("status")))
+	default:
+		notFound(w)
+	}
+}
+
+func (h *handler) serveUser(w http.ResponseWriter, segments []string) {
+	if len(segments) == 0 {
+		notFound(w)
+		return
+	}
+	user := h.fixtures.user(segments[0])
+	if user == nil {
+		notFound(w)
+		return
+	}
+	switch {
+	case len(segments) == 1:
+		writeJSON(w, &github.User{
+			Login:   github.String(user.Login),
+			Company: github.String(user.Company),
+		})
+	case len(segments) == 2 && segments[1] == "orgs":
+		orgs := make([]*github.Organization, 0, len(user.Orgs))
+		for _, org := range user.Orgs {
+			orgs = append(orgs, &github.Organization{Login: github.String(org)})
+		}
+		writeJSON(w, orgs)
+	default:
+		notFound(w)
+	}
+}
+
+func (h *handler) serveOrgRepos(w http.ResponseWriter, r *http.Request, org string) {
+	ret := []*github.Repository{}
+	for i := range h.fixtures.Repos {
+		if strings.EqualFold(h.fixtures.Repos[i].Owner, org) {
+			ret = append(ret, repository(r, &h.fixtures.Repos[i]))
+		}
+	}
+	if len(ret) == 0 {
+		notFound(w)
+		return
+	}
+	writeJSON(w, ret)
+}
+
+// serveCodeSearch searches the files of all repos. It supports the `repo`, `org`, `user`,
+// `path`, `filename` and `in:file` qualifiers; all other terms must occur in the file.
+// nolint: gocognit
+func (h *handler) serveCodeSearch(w http.ResponseWriter, r *http.Request) {
+	var terms []string
+	qualifiers := map[string]string{}
+	for _, m := range searchTerm.FindAllStringSubmatch(r.URL.Query().Get("q"), -1) {
+		term := m[2] + m[3]
+		if m[1] != "" {
+			qualifiers[strings.ToLower(m[1])] = term
+		} else {
+			terms = append(terms, strings.ToLower(term))
+		}
+	}
+
+	result := &github.CodeSearchResult{CodeResults: []*github.CodeResult{}}
+	for i := range h.fixtures.Repos {
+		repo := &h.fixtures.Repos[i]
+		if q, ok := qualifiers["repo"]; ok && !strings.EqualFold(q, repo.FullName()) {
+			continue
+		}
+		if q, ok := qualifiers["org"]; ok && !strings.EqualFold(q, repo.Owner) {
+			continue
+		}
+		if q, ok := qualifiers["user"]; ok && !strings.EqualFold(q, repo.Owner) {
+			continue
+		}
+		for _, filename := range sortedFiles(repo) {
+			if q, ok := qualifiers["path"]; ok && !strings.HasPrefix(filename, strings.Trim(q, "/")) {
+				continue
+			}
+			if q, ok := qualifiers["filename"]; ok && path.Base(filename) != q {
+				continue
+			}
+			if !containsAll(strings.ToLower(repo.Files[filename]), terms) {
+				continue
+			}
+			result.CodeResults = append(result.CodeResults, &github.CodeResult{
+				Name:       github.String(path.Base(filename)),
+				Path:       github.String(filename),
+				Repository: repository(r, repo),
+			})
+		}
+	}
+	result.Total = github.Int(len(result.CodeResults))
+	result.IncompleteResults = github.Bool(false)
+	writeJSON(w, result)
+}
+
+func containsAll(s string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(s, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func serveRateLimit(w http.ResponseWriter) {
+	rate := github.Rate{
+		Limit:     rateLimit,
+		Remaining: rateLimit,
+		Reset:     github.Timestamp{Time: time.Now().Add(time.Hour)},
+	}
+	writeJSON(w, map[string]interface{}{
+		"resources": map[string]github.Rate{
+			"core":    rate,
+			"search":  rate,
+			"graphql": rate,
+		},
+		"rate": rate,
+	})
+}
+
+// serveTarball serves the files of repo as a gzipped tarball, with all files in a
+// top-level directory like GitHub's.
+func serveTarball(w http.ResponseWriter, repo *Repo) {
+	w.Header().Set("Content-Type", "application/x-gzip")
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	dir := fmt.Sprintf("%s-%s-%s/", repo.Owner, repo.Name, headSHA(repo))
+	for _, filename := range sortedFiles(repo) {
+		content := repo.Files[filename]
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     dir + filename,
+			Mode:     0o644, // nolint: gomnd
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}); err != nil {
+			return
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			return
+		}
+	}
+	tw.Close()
+	gz.Close()
+}
+
+func sortedFiles(repo *Repo) []string {
+	ret := make([]string, 0, len(repo.Files))
+	for filename := range repo.Files {
+		ret = append(ret, filename)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func headSHA(repo *Repo) string {
+	if len(repo.Commits) > 0 {
+		return repo.Commits[0].SHA
+	}
+	return strings.Repeat("0", 40) // nolint: gomnd
+}
+
+func repository(r *http.Request, repo *Repo) *github.Repository {
+	baseURL := fmt.Sprintf("http://%s", r.Host)
+	apiURL := fmt.Sprintf("%s/repos/%s", baseURL, repo.FullName())
+	return &github.Repository{
+		Name:          github.String(repo.Name),
+		FullName:      github.String(repo.FullName()),
+		Owner:         &github.User{Login: github.String(repo.Owner)},
+		DefaultBranch: github.String(repo.DefaultBranch),
+		Archived:      github.Bool(repo.Archived),
+		Fork:          github.Bool(repo.Fork),
+		URL:           github.String(apiURL),
+		HTMLURL:       github.String(fmt.Sprintf("https://github.com/%s", repo.FullName())),
+		ArchiveURL:    github.String(apiURL + "/{archive_format}{/ref}"),
+	}
+}
+
+func pullRequests(repo *Repo, state string) []*github.PullRequest {
+	if state == "" {
+		state = "open"
+	}
+	ret := []*github.PullRequest{}
+	for i := range repo.PullRequests {
+		pr := &repo.PullRequests[i]
+		if state != "all" && pr.state() != state {
+			continue
+		}
+		labels := make([]*github.Label, 0, len(pr.Labels))
+		for _, label := range pr.Labels {
+			labels = append(labels, &github.Label{Name: github.String(label)})
+		}
+		ret = append(ret, &github.PullRequest{
+			Number:   github.Int(pr.Number),
+			State:    github.String(pr.state()),
+			MergedAt: pr.MergedAt,
+			Head:     &github.PullRequestBranch{SHA: github.String(pr.HeadSHA)},
+			Labels:   labels,
+		})
+	}
+	return ret
+}
+
+func checkRuns(repo *Repo, sha string) *github.ListCheckRunsResults {
+	ret := &github.ListCheckRunsResults{CheckRuns: []*github.CheckRun{}}
+	for _, cr := range repo.CheckRuns[sha] {
+		ret.CheckRuns = append(ret.CheckRuns, &github.CheckRun{
+			HeadSHA:    github.String(sha),
+			Status:     github.String(cr.Status),
+			Conclusion: github.String(cr.Conclusion),
+			App:        &github.App{Slug: github.String(cr.App)},
+		})
+	}
+	ret.Total = github.Int(len(ret.CheckRuns))
+	return ret
+}
+
+func statuses(repo *Repo, sha string) []*github.RepoStatus {
+	ret := []*github.RepoStatus{}
+	for _, status := range repo.Statuses[sha] {
+		ret = append(ret, &github.RepoStatus{
+			Context: github.String(status.Context),
+			State:   github.String(status.State),
+		})
+	}
+	return ret
+}
+
+func releases(repo *Repo) []*github.RepositoryRelease {
+	ret := []*github.RepositoryRelease{}
+	for _, release := range repo.Releases {
+		target := release.TargetCommitish
+		if target == "" {
+			target = repo.DefaultBranch
+		}
+		ret = append(ret, &github.RepositoryRelease{
+			ID:              github.Int64(release.ID),
+			TagName:         github.String(release.TagName),
+			TargetCommitish: github.String(target),
+		})
+	}
+	return ret
+}
+
+func releaseAssets(repo *Repo, id string) ([]*github.ReleaseAsset, bool) {
+	for _, release := range repo.Releases {
+		if strconv.FormatInt(release.ID, 10) != id {
+			continue
+		}
+		ret := []*github.ReleaseAsset{}
+		for _, asset := range release.Assets {
+			ret = append(ret, &github.ReleaseAsset{Name: github.String(asset)})
+		}
+		return ret, true
+	}
+	return nil, false
+}
+
+func gitTag(repo *Repo, sha string) (*github.Tag, bool) {
+	for _, tag := range repo.Tags {
+		if tag.SHA != sha {
+			continue
+		}
+		reason := "unsigned"
+		if tag.Verified {
+			reason = "valid"
+		}
+		return &github.Tag{
+			Tag: github.String(tag.Name),
+			SHA: github.String(tag.SHA),
+			Verification: &github.SignatureVerification{
+				Verified: github.Bool(tag.Verified),
+				Reason:   github.String(reason),
+			},
+		}, true
+	}
+	return nil, false
+}
+
+func branches(repo *Repo) []*github.Branch {
+	names := map[string]bool{repo.DefaultBranch: true}
+	for _, name := range repo.Branches {
+		names[name] = true
+	}
+	for name := range repo.BranchProtection {
+		names[name] = true
+	}
+	ret := []*github.Branch{}
+	for name := range names {
+		_, protected := repo.BranchProtection[name]
+		ret = append(ret, &github.Branch{
+			Name:      github.String(name),
+			Protected: github.Bool(protected),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].GetName() < ret[j].GetName() })
+	return ret
+}
+
+func branchProtection(p *BranchProtection) *github.Protection {
+	ret := &github.Protection{
+		EnforceAdmins:        &github.AdminEnforcement{Enabled: p.EnforceAdmins},
+		RequireLinearHistory: &github.RequireLinearHistory{Enabled: p.RequireLinearHistory},
+		AllowForcePushes:     &github.AllowForcePushes{Enabled: p.AllowForcePushes},
+		AllowDeletions:       &github.AllowDeletions{Enabled: p.AllowDeletions},
+	}
+	if len(p.RequiredStatusChecks) > 0 {
+		ret.RequiredStatusChecks = &github.RequiredStatusChecks{
+			Strict:   p.StrictStatusChecks,
+			Contexts: p.RequiredStatusChecks,
+		}
+	}
+	if p.RequiredApprovingReviews > 0 {
+		ret.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcement{
+			DismissStaleReviews:          p.DismissStaleReviews,
+			RequireCodeOwnerReviews:      p.RequireCodeOwnerReviews,
+			RequiredApprovingReviewCount: p.RequiredApprovingReviews,
+		}
+	}
+	return ret
+}
+
+func contributors(repo *Repo) []*github.Contributor {
+	ret := []*github.Contributor{}
+	for _, contributor := range repo.Contributors {
+		ret = append(ret, &github.Contributor{
+			Login:         github.String(contributor.Login),
+			Contributions: github.Int(contributor.Contributions),
+		})
+	}
+	return ret
+}
+
+func workflowRuns(repo *Repo, workflow, status string) *github.WorkflowRuns {
+	ret := &github.WorkflowRuns{WorkflowRuns: []*github.WorkflowRun{}}
+	for _, run := range repo.WorkflowRuns[workflow] {
+		if status != "" && run.Status != status && run.Conclusion != status {
+			continue
+		}
+		ret.WorkflowRuns = append(ret.WorkflowRuns, &github.WorkflowRun{
+			Status:     github.String(run.Status),
+			Conclusion: github.String(run.Conclusion),
+			HTMLURL: github.String(fmt.Sprintf("https://github.com/%s/actions/runs/%d",
+				repo.FullName(), len(ret.WorkflowRuns)+1)),
+		})
+	}
+	ret.TotalCount = github.Int(len(ret.WorkflowRuns))
+	return ret
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	// nolint: errcheck
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "Not Found")
+}
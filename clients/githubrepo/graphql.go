@@ -16,7 +16,7 @@ package githubrepo
 
 import (
 	"context"
-	"fmt"
+	"errors"
 
 	"github.com/shurcooL/githubv4"
 
@@ -31,6 +31,10 @@ const (
 	commitsToAnalyze      = 30
 )
 
+// ErrGraphQLUnavailable indicates the Client was created without a GraphQL client,
+// e.g. because requests are unauthenticated.
+var ErrGraphQLUnavailable = errors.New("GitHub GraphQL API unavailable")
+
 // nolint: govet
 type graphqlData struct {
 	Repository struct {
@@ -89,6 +93,9 @@ type graphqlHandler struct {
 }
 
 func (handler *graphqlHandler) init(ctx context.Context, owner, repo string) error {
+	if handler.client == nil {
+		return nil
+	}
 	vars := map[string]interface{}{
 		"owner":                 githubv4.String(owner),
 		"name":                  githubv4.String(repo),
@@ -100,7 +107,7 @@ func (handler *graphqlHandler) init(ctx context.Context, owner, repo string) err
 	handler.data = new(graphqlData)
 	if err := handler.client.Query(ctx, handler.data, vars); err != nil {
 		// nolint: wrapcheck
-		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("githubv4.Query: %v", err))
+		return sce.CreateFrom(err, "githubv4.Query")
 	}
 	handler.archived = bool(handler.data.Repository.IsArchived)
 	handler.prs = pullRequestFrom(handler.data)
@@ -110,18 +117,30 @@ func (handler *graphqlHandler) init(ctx context.Context, owner, repo string) err
 }
 
 func (handler *graphqlHandler) getMergedPRs() ([]clients.PullRequest, error) {
+	if handler.data == nil {
+		return nil, ErrGraphQLUnavailable
+	}
 	return handler.prs, nil
 }
 
 func (handler *graphqlHandler) getDefaultBranch() (clients.BranchRef, error) {
+	if handler.data == nil {
+		return clients.BranchRef{}, ErrGraphQLUnavailable
+	}
 	return handler.defaultBranchRef, nil
 }
 
 func (handler *graphqlHandler) getCommits() ([]clients.Commit, error) {
+	if handler.data == nil {
+		return nil, ErrGraphQLUnavailable
+	}
 	return handler.commits, nil
 }
 
 func (handler *graphqlHandler) isArchived() (bool, error) {
+	if handler.data == nil {
+		return false, ErrGraphQLUnavailable
+	}
 	return handler.archived, nil
 }
 
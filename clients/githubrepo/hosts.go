@@ -0,0 +1,183 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/shurcooL/githubv4"
+	"gopkg.in/yaml.v2"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+// Host holds the API endpoints of a GitHub Enterprise Server host.
+type Host struct {
+	// Name is the host name used in repo URLs, e.g. `github.example.com`.
+	Name string `yaml:"host"`
+	// APIURL defaults to `https://<Name>/api/v3/`.
+	APIURL string `yaml:"api"`
+	// UploadURL defaults to `https://<Name>/api/uploads/`.
+	UploadURL string `yaml:"uploads"`
+	// GraphQLURL defaults to `https://<Name>/api/graphql`.
+	GraphQLURL string `yaml:"graphql"`
+}
+
+type hostsFile struct {
+	Hosts []Host `yaml:"hosts"`
+}
+
+var enterpriseHosts = struct {
+	sync.RWMutex
+	hosts map[string]Host
+}{hosts: map[string]Host{}}
+
+var githubAPIURL = struct {
+	sync.RWMutex
+	url *url.URL
+}{}
+
+// RegisterHost configures the endpoints of a GitHub Enterprise Server host, filling
+// in the default endpoints for those left empty, and allows repos on it.
+func RegisterHost(host Host) error {
+	host.Name = strings.ToLower(strings.TrimSpace(host.Name))
+	if host.Name == "" {
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal, "host name is empty")
+	}
+	if host.APIURL == "" {
+		host.APIURL = fmt.Sprintf("https://%s/api/v3/", host.Name)
+	}
+	if host.UploadURL == "" {
+		host.UploadURL = fmt.Sprintf("https://%s/api/uploads/", host.Name)
+	}
+	if host.GraphQLURL == "" {
+		host.GraphQLURL = fmt.Sprintf("https://%s/api/graphql", host.Name)
+	}
+
+	enterpriseHosts.Lock()
+	defer enterpriseHosts.Unlock()
+	enterpriseHosts.hosts[host.Name] = host
+	repos.RegisterGitHubHost(host.Name)
+	return nil
+}
+
+// EnterpriseHosts returns the names of the registered GitHub Enterprise Server hosts, sorted.
+func EnterpriseHosts() []string {
+	enterpriseHosts.RLock()
+	defer enterpriseHosts.RUnlock()
+	names := make([]string, 0, len(enterpriseHosts.hosts))
+	for name := range enterpriseHosts.hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// enterpriseAPIURL returns the REST API URL, without a trailing slash, of the
+// registered GitHub Enterprise Server host whose API is served on requestHost, the
+// `host[:port]` of a request.
+func enterpriseAPIURL(requestHost string) (string, bool) {
+	enterpriseHosts.RLock()
+	defer enterpriseHosts.RUnlock()
+	for _, host := range enterpriseHosts.hosts {
+		for _, endpoint := range []string{host.APIURL, host.GraphQLURL} {
+			if u, err := url.Parse(endpoint); err == nil && strings.EqualFold(u.Host, requestHost) {
+				return strings.TrimSuffix(host.APIURL, "/"), true
+			}
+		}
+	}
+	return "", false
+}
+
+// LoadHosts registers the GitHub Enterprise Server hosts listed in a YAML file of the form:
+//
+//   hosts:
+//     - host: github.example.com
+//       api: https://github.example.com/api/v3/
+//       uploads: https://github.example.com/api/uploads/
+//       graphql: https://github.example.com/api/graphql
+func LoadHosts(filename string) error {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("error reading hosts file: %w", err)
+	}
+	var f hostsFile
+	if err := yaml.Unmarshal(content, &f); err != nil {
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("yaml.Unmarshal: %v", err))
+	}
+	for _, host := range f.Hosts {
+		if err := RegisterHost(host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetGitHubAPIURL makes repos on github.com use the GitHub API at apiURL instead of
+// https://api.github.com/, e.g. a fake GitHub server in tests. The GraphQL API is
+// expected at `<apiURL>/graphql`.
+func SetGitHubAPIURL(apiURL string) error {
+	u, err := url.Parse(strings.TrimSuffix(apiURL, "/") + "/")
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("invalid GitHub API URL: %s", apiURL))
+	}
+	githubAPIURL.Lock()
+	defer githubAPIURL.Unlock()
+	githubAPIURL.url = u
+	return nil
+}
+
+// NewClients returns the REST and GraphQL clients for the host of a repo URL,
+// which is either github.com or a registered GitHub Enterprise Server host.
+func NewClients(httpClient *http.Client, hostName string) (*github.Client, *githubv4.Client, error) {
+	hostName = strings.ToLower(hostName)
+	if hostName == "github.com" {
+		githubAPIURL.RLock()
+		apiURL := githubAPIURL.url
+		githubAPIURL.RUnlock()
+		if apiURL == nil {
+			return github.NewClient(httpClient), githubv4.NewClient(httpClient), nil
+		}
+		client := github.NewClient(httpClient)
+		client.BaseURL = apiURL
+		client.UploadURL = apiURL
+		return client, githubv4.NewEnterpriseClient(apiURL.String()+"graphql", httpClient), nil
+	}
+
+	enterpriseHosts.RLock()
+	host, ok := enterpriseHosts.hosts[hostName]
+	enterpriseHosts.RUnlock()
+	if !ok {
+		//nolint:wrapcheck
+		return nil, nil, sce.Create(repos.ErrorUnsupportedHost, hostName)
+	}
+	client, err := github.NewEnterpriseClient(host.APIURL, host.UploadURL, httpClient)
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, nil, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("github.NewEnterpriseClient: %v", err))
+	}
+	return client, githubv4.NewEnterpriseClient(host.GraphQLURL, httpClient), nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+func TestLoadHosts(t *testing.T) {
+	t.Parallel()
+	if err := LoadHosts("testdata/hosts.yaml"); err != nil {
+		t.Fatalf("LoadHosts: %v", err)
+	}
+	registered := map[string]bool{}
+	for _, host := range EnterpriseHosts() {
+		registered[host] = true
+	}
+	for _, host := range []string{"ghes.example.com", "github.corp.example.com"} {
+		if !registered[host] {
+			t.Errorf("EnterpriseHosts: missing %s", host)
+		}
+	}
+
+	testcases := []struct {
+		name        string
+		host        string
+		expectedAPI string
+	}{
+		{
+			name:        "GitHub",
+			host:        "github.com",
+			expectedAPI: "https://api.github.com/",
+		},
+		{
+			name:        "DefaultEndpoints",
+			host:        "ghes.example.com",
+			expectedAPI: "https://ghes.example.com/api/v3/",
+		},
+		{
+			name:        "CustomEndpoints",
+			host:        "GitHub.Corp.Example.com",
+			expectedAPI: "https://api.github.corp.example.com/api/v3/",
+		},
+	}
+	for _, tt := range testcases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, graphClient, err := NewClients(http.DefaultClient, tt.host)
+			if err != nil {
+				t.Fatalf("NewClients: %v", err)
+			}
+			if graphClient == nil {
+				t.Error("expected a GraphQL client")
+			}
+			if got := client.BaseURL.String(); got != tt.expectedAPI {
+				t.Errorf("expected API URL %s, got %s", tt.expectedAPI, got)
+			}
+			repoURL := repos.RepoURL{Host: tt.host, Owner: "owner", Repo: "repo"}
+			if err := repoURL.ValidGitHubURL(); err != nil {
+				t.Errorf("ValidGitHubURL: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewClientsUnsupportedHost(t *testing.T) {
+	t.Parallel()
+	if _, _, err := NewClients(http.DefaultClient, "gitlab.com"); !errors.Is(err, repos.ErrorUnsupportedHost) {
+		t.Errorf("expected ErrorUnsupportedHost, got: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+const orgReposPerPage = 100
+
+// Visibility values accepted by OrgRepoFilter.
+const (
+	VisibilityAll      = "all"
+	VisibilityPublic   = "public"
+	VisibilityPrivate  = "private"
+	VisibilityInternal = "internal"
+)
+
+var errInvalidVisibility = errors.New("invalid visibility")
+
+// OrgRepoFilter selects which repositories of an organisation are returned by ListOrgRepos.
+type OrgRepoFilter struct {
+	// Visibility is one of VisibilityAll, VisibilityPublic, VisibilityPrivate or VisibilityInternal.
+	Visibility string
+	// Topics, if non-empty, only selects repositories which have at least one of the topics.
+	Topics          []string
+	IncludeArchived bool
+	IncludeForks    bool
+}
+
+func (filter *OrgRepoFilter) matches(repo *github.Repository) bool {
+	if repo.GetArchived() && !filter.IncludeArchived {
+		return false
+	}
+	if repo.GetFork() && !filter.IncludeForks {
+		return false
+	}
+	if len(filter.Topics) == 0 {
+		return true
+	}
+	for _, topic := range repo.Topics {
+		for _, want := range filter.Topics {
+			if strings.EqualFold(topic, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ListOrgRepos returns the repositories of a GitHub organisation on host which match the
+// filter. client must be a client of host's API, as returned by NewClients.
+func ListOrgRepos(ctx context.Context, client *github.Client,
+	host, org string, filter OrgRepoFilter) ([]repos.RepoURL, error) {
+	visibility := filter.Visibility
+	switch visibility {
+	case "":
+		visibility = VisibilityAll
+	case VisibilityAll, VisibilityPublic, VisibilityPrivate, VisibilityInternal:
+	default:
+		//nolint:wrapcheck
+		return nil, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("%v: %s", errInvalidVisibility, visibility))
+	}
+
+	opts := &github.RepositoryListByOrgOptions{
+		Type: visibility,
+		ListOptions: github.ListOptions{
+			PerPage: orgReposPerPage,
+		},
+	}
+	ret := []repos.RepoURL{}
+	for {
+		page, resp, err := client.Repositories.ListByOrg(ctx, org, opts)
+		if err != nil {
+			//nolint:wrapcheck
+			return nil, sce.CreateFrom(err, "Repositories.ListByOrg")
+		}
+		for _, repo := range page {
+			if !filter.matches(repo) {
+				continue
+			}
+			ret = append(ret, repos.RepoURL{
+				Host:  host,
+				Owner: repo.GetOwner().GetLogin(),
+				Repo:  repo.GetName(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return ret, nil
+}
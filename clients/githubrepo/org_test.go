@@ -0,0 +1,129 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v32/github"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+// orgReposPages are the pages of repositories of the org served by newOrgServer.
+var orgReposPages = []string{
+	`[
+		{"name": "repo1", "owner": {"login": "org"}},
+		{"name": "archived", "owner": {"login": "org"}, "archived": true},
+		{"name": "fork", "owner": {"login": "org"}, "fork": true, "topics": ["security"]}
+	]`,
+	`[
+		{"name": "repo2", "owner": {"login": "org"}, "topics": ["Security", "go"]}
+	]`,
+}
+
+// newOrgServer serves the repositories of an org, and records the type of repositories
+// requested.
+func newOrgServer(t *testing.T, types *[]string) *github.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/orgs/org/repos" {
+			http.NotFound(w, r)
+			return
+		}
+		*types = append(*types, r.URL.Query().Get("type"))
+		page := 1
+		fmt.Sscan(r.URL.Query().Get("page"), &page) // nolint: errcheck
+		if page < len(orgReposPages) {
+			next := *r.URL
+			next.RawQuery = url.Values{"page": {fmt.Sprint(page + 1)}}.Encode()
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s>; rel="next"`, "http://"+r.Host, next.RequestURI()))
+		}
+		fmt.Fprint(w, orgReposPages[page-1])
+	}))
+	t.Cleanup(server.Close)
+	client := github.NewClient(server.Client())
+	baseURL, err := url.Parse(server.URL + "/api/v3/")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+	client.BaseURL = baseURL
+	return client
+}
+
+func TestListOrgRepos(t *testing.T) {
+	t.Parallel()
+	repo := func(name string) repos.RepoURL {
+		return repos.RepoURL{Host: "ghes.example.com", Owner: "org", Repo: name}
+	}
+	tests := []struct {
+		name     string
+		filter   OrgRepoFilter
+		want     []repos.RepoURL
+		wantType string
+		wantErr  error
+	}{
+		{
+			name:     "default filter",
+			want:     []repos.RepoURL{repo("repo1"), repo("repo2")},
+			wantType: VisibilityAll,
+		},
+		{
+			name:     "archived and forks",
+			filter:   OrgRepoFilter{Visibility: VisibilityPublic, IncludeArchived: true, IncludeForks: true},
+			want:     []repos.RepoURL{repo("repo1"), repo("archived"), repo("fork"), repo("repo2")},
+			wantType: VisibilityPublic,
+		},
+		{
+			name:     "topics",
+			filter:   OrgRepoFilter{Topics: []string{"security"}, IncludeForks: true},
+			want:     []repos.RepoURL{repo("fork"), repo("repo2")},
+			wantType: VisibilityAll,
+		},
+		{
+			name:    "invalid visibility",
+			filter:  OrgRepoFilter{Visibility: "secret"},
+			wantErr: sce.ErrScorecardInternal,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Re-initializing variable so it is not changed while executing the closure below
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var types []string
+			client := newOrgServer(t, &types)
+			got, err := ListOrgRepos(context.Background(), client, "ghes.example.com", "org", tt.filter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListOrgRepos error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); tt.wantErr == nil && diff != "" {
+				t.Errorf("unexpected repos (-want +got):\n%s", diff)
+			}
+			for _, typ := range types {
+				if typ != tt.wantType {
+					t.Errorf("requested type %q, want %q", typ, tt.wantType)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"context"
+
+	"github.com/google/go-github/v32/github"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+// ResolveRedirects moves repoURL to the current owner and name of a renamed or
+// transferred repository. GitHub serves such repositories under their old names with
+// a redirect, which the HTTP client follows, so the current name is that of the
+// repository returned. The old name is recorded in repoURL's Metadata.
+func ResolveRedirects(ctx context.Context, client *github.Client, repoURL *repos.RepoURL) error {
+	repo, _, err := client.Repositories.Get(ctx, repoURL.Owner, repoURL.Repo)
+	if err != nil {
+		return sce.CreateFrom(err, "Client.Repositories.Get")
+	}
+	repoURL.Rename(repo.GetOwner().GetLogin(), repo.GetName())
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v32/github"
+
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+func TestResolveRedirects(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/old-owner/old-name", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/repositories/42", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/repositories/42", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "New-Name", "owner": {"login": "New-Owner"}}`)
+	})
+	mux.HandleFunc("/repos/new-owner/new-name", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "New-Name", "owner": {"login": "New-Owner"}}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(server.Client())
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = baseURL
+
+	tests := []struct {
+		name string
+		repo repos.RepoURL
+		want repos.RepoURL
+	}{
+		{
+			name: "renamed",
+			repo: repos.RepoURL{Host: "github.com", Owner: "old-owner", Repo: "old-name"},
+			want: repos.RepoURL{
+				Host: "github.com", Owner: "new-owner", Repo: "new-name",
+				Metadata: []string{"original-repo=github.com/old-owner/old-name"},
+			},
+		},
+		{
+			name: "current",
+			repo: repos.RepoURL{Host: "github.com", Owner: "new-owner", Repo: "new-name"},
+			want: repos.RepoURL{Host: "github.com", Owner: "new-owner", Repo: "new-name"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			repoURL := tt.repo
+			if err := ResolveRedirects(context.Background(), client, &repoURL); err != nil {
+				t.Fatalf("ResolveRedirects() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, repoURL); diff != "" {
+				t.Errorf("unexpected repo (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -69,14 +69,14 @@ type tarballHandler struct {
 	files       []string
 }
 
-func (handler *tarballHandler) init(ctx context.Context, repo *github.Repository) error {
+func (handler *tarballHandler) init(ctx context.Context, client *github.Client, repo *github.Repository) error {
 	// Cleanup any previous state.
 	if err := handler.cleanup(); err != nil {
 		return fmt.Errorf("error during githubrepo cleanup: %w", err)
 	}
 
 	// Setup temp dir/files and download repo tarball.
-	if err := handler.getTarball(ctx, repo); errors.Is(err, errTarballNotFound) {
+	if err := handler.getTarball(ctx, client, repo); errors.Is(err, errTarballNotFound) {
 		return nil
 	} else if err != nil {
 		return err
@@ -86,27 +86,16 @@ func (handler *tarballHandler) init(ctx context.Context, repo *github.Repository
 	return handler.extractTarball()
 }
 
-func (handler *tarballHandler) getTarball(ctx context.Context, repo *github.Repository) error {
+// getTarball downloads the tarball of repo using client, so that the download goes
+// through the same transport as all other requests to GitHub.
+func (handler *tarballHandler) getTarball(ctx context.Context, client *github.Client, repo *github.Repository) error {
 	url := repo.GetArchiveURL()
 	url = strings.Replace(url, "{archive_format}", "tarball/", 1)
 	url = strings.Replace(url, "{/ref}", "", 1)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := client.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		//nolint:wrapcheck
-		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("http.NewRequestWithContext: %v", err))
-	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		//nolint:wrapcheck
-		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("http.DefaultClient.Do: %v", err))
-	}
-	defer resp.Body.Close()
-
-	// Handle 400/404 errors
-	switch resp.StatusCode {
-	case http.StatusNotFound, http.StatusBadRequest:
-		//nolint:wrapcheck
-		return sce.CreateInternal(errTarballNotFound, fmt.Sprintf("%v: %v: %v", errTarballNotFound, *repo.URL, err))
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("client.NewRequest: %v", err))
 	}
 
 	// Create a temp file. This automatically appends a random number to the name.
@@ -115,18 +104,28 @@ func (handler *tarballHandler) getTarball(ctx context.Context, repo *github.Repo
 		//nolint:wrapcheck
 		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("ioutil.TempDir: %v", err))
 	}
+	handler.tempDir = tempDir
 	repoFile, err := ioutil.TempFile(tempDir, repoFilename)
 	if err != nil {
 		//nolint:wrapcheck
 		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("ioutil.TempFile: %v", err))
 	}
 	defer repoFile.Close()
-	if _, err := io.Copy(repoFile, resp.Body); err != nil {
+
+	resp, err := client.Do(ctx, req, repoFile)
+	// Handle 400/404 errors
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusNotFound, http.StatusBadRequest:
+			//nolint:wrapcheck
+			return sce.CreateInternal(errTarballNotFound, fmt.Sprintf("%v: %v: %v", errTarballNotFound, *repo.URL, err))
+		}
+	}
+	if err != nil {
 		//nolint:wrapcheck
-		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("io.Copy: %v", err))
+		return sce.CreateFrom(err, "client.Do")
 	}
 
-	handler.tempDir = tempDir
 	handler.tempTarFile = repoFile.Name()
 	return nil
 }
@@ -226,6 +225,10 @@ func (handler *tarballHandler) listFiles(predicate func(string) (bool, error)) (
 
 func (handler *tarballHandler) getFileContent(filename string) ([]byte, error) {
 	content, err := ioutil.ReadFile(filepath.Join(handler.tempDir, filename))
+	if os.IsNotExist(err) {
+		//nolint:wrapcheck
+		return content, sce.Create(sce.ErrNotFound, fmt.Sprintf("ioutil.ReadFile: %v", err))
+	}
 	if err != nil {
 		//nolint:wrapcheck
 		return content, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("ioutil.ReadFile: %v", err))
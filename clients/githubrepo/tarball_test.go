@@ -25,6 +25,8 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+
+	sce "github.com/ossf/scorecard/v2/errors"
 )
 
 type listfileTest struct {
@@ -109,7 +111,7 @@ func TestExtractTarball(t *testing.T) {
 				},
 				{
 					filename: "does/not/exist",
-					err:      os.ErrNotExist,
+					err:      sce.ErrNotFound,
 				},
 			},
 		},
@@ -148,7 +150,7 @@ func TestExtractTarball(t *testing.T) {
 			// Test GetFileContent API.
 			for _, getcontenttest := range testcase.getcontentTests {
 				content, err := handler.getFileContent(getcontenttest.filename)
-				if getcontenttest.err != nil && !errors.As(err, &getcontenttest.err) {
+				if getcontenttest.err != nil && !errors.Is(err, getcontenttest.err) {
 					t.Errorf("test failed: expected - %v, got - %v", getcontenttest.err, err)
 				}
 				if getcontenttest.err == nil && !cmp.Equal(getcontenttest.output, content) {
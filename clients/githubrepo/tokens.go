@@ -0,0 +1,168 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+)
+
+// GitHub rate limit resources, as reported in the `X-RateLimit-Resource` header.
+const (
+	resourceCore    = "core"
+	resourceSearch  = "search"
+	resourceGraphQL = "graphql"
+)
+
+// budget is the rate limit of a token for a single resource.
+type budget struct {
+	reset     time.Time
+	remaining int
+}
+
+func makeTokenAccessor(accessTokens []string) tokenAccessor {
+	budgets := make([]map[string]budget, len(accessTokens))
+	for i := range budgets {
+		budgets[i] = make(map[string]budget)
+	}
+	return &headroomAccessor{
+		accessTokens: accessTokens,
+		budgets:      budgets,
+		now:          time.Now,
+	}
+}
+
+// headroomAccessor picks the token with the most remaining rate limit for the resource
+// of each request, round robin among tokens with equal headroom. Tokens are assumed to
+// have full budget until GitHub reports otherwise. It only blocks when all tokens are
+// exhausted, until the earliest reset.
+type headroomAccessor struct {
+	now          func() time.Time
+	accessTokens []string
+	// budgets holds the last known rate limit per resource of each token.
+	budgets []map[string]budget
+	counter int
+	mu      sync.Mutex
+}
+
+func (h *headroomAccessor) next(r *http.Request) (int, string, error) {
+	resource := resourceOf(r)
+	for {
+		index, wait := h.pick(resource)
+		if index >= 0 {
+			ctx, err := tag.New(r.Context(), tag.Upsert(TokenIndex, fmt.Sprint(index)))
+			if err != nil {
+				return 0, "", fmt.Errorf("error updating context: %w", err)
+			}
+			*r = *r.WithContext(ctx)
+			return index, h.accessTokens[index], nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return 0, "", fmt.Errorf("error waiting for rate limit reset: %w", r.Context().Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// pick returns the index of the token with the most headroom for resource, or -1
+// along with the time until the earliest reset if all tokens are exhausted.
+func (h *headroomAccessor) pick(resource string) (int, time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.now()
+	best, bestHeadroom := -1, 0
+	earliestReset := time.Time{}
+	start := h.counter
+	h.counter++
+	for i := range h.accessTokens {
+		index := (start + i) % len(h.accessTokens)
+		headroom := math.MaxInt32
+		if b, ok := h.budgets[index][resource]; ok && now.Before(b.reset) {
+			headroom = b.remaining
+			if earliestReset.IsZero() || b.reset.Before(earliestReset) {
+				earliestReset = b.reset
+			}
+		}
+		if headroom > bestHeadroom {
+			best, bestHeadroom = index, headroom
+		}
+	}
+	if best < 0 {
+		return -1, earliestReset.Sub(now)
+	}
+	return best, 0
+}
+
+func (h *headroomAccessor) update(index int, r *http.Request, resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = resourceOf(r)
+	}
+
+	h.mu.Lock()
+	h.budgets[index][resource] = budget{
+		remaining: remaining,
+		reset:     time.Unix(reset, 0),
+	}
+	h.mu.Unlock()
+
+	ctx, err := tag.New(r.Context(),
+		tag.Upsert(TokenIndex, fmt.Sprint(index)),
+		tag.Upsert(ResourceType, resource))
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, RemainingTokens.M(int64(remaining)))
+}
+
+// resourceOf returns the rate limit resource a request counts against.
+func resourceOf(r *http.Request) string {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/graphql"):
+		return resourceGraphQL
+	case strings.HasPrefix(r.URL.Path, "/search/") || strings.Contains(r.URL.Path, "/api/v3/search/"):
+		return resourceSearch
+	default:
+		return resourceCore
+	}
+}
+
+// isRateLimited returns true if the request was rejected because the primary rate limit is exhausted.
+func isRateLimited(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	return resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
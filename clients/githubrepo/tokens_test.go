@@ -0,0 +1,128 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeRateLimitServer serves requests while tracking the remaining rate limit per token.
+type fakeRateLimitServer struct {
+	remaining map[string]int
+	used      []string
+	mu        sync.Mutex
+}
+
+func (s *fakeRateLimitServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.used = append(s.used, token)
+	w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+	w.Header().Set("X-RateLimit-Resource", resourceOf(r))
+	if s.remaining[token] <= 0 {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	s.remaining[token]--
+	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(s.remaining[token]))
+}
+
+func TestGitHubTransportPicksTokenWithMostHeadroom(t *testing.T) {
+	t.Parallel()
+	fake := &fakeRateLimitServer{
+		remaining: map[string]int{"a": 1, "b": 3, "c": 0},
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client := &http.Client{
+		Transport: MakeGitHubTransport(http.DefaultTransport, []string{"a", "b", "c"}),
+	}
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get(server.URL + "/repos/owner/repo")
+		if err != nil {
+			t.Fatalf("client.Get: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("request %d: unexpected status %d", i, resp.StatusCode)
+		}
+	}
+	// The first requests try each unknown token in turn, "c" is rejected and retried with
+	// another token, after which the token with the most headroom is picked.
+	expected := []string{"a", "b", "c", "b", "b"}
+	if diff := cmp.Diff(expected, fake.used); diff != "" {
+		t.Errorf("unexpected tokens used (-want +got):\n%s", diff)
+	}
+}
+
+func TestGitHubTransportBlocksWhenAllTokensExhausted(t *testing.T) {
+	t.Parallel()
+	fake := &fakeRateLimitServer{
+		remaining: map[string]int{"a": 0, "b": 0},
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client := &http.Client{
+		Transport: MakeGitHubTransport(http.DefaultTransport, []string{"a", "b"}),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/repos/owner/repo", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequestWithContext: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected error waiting for rate limit reset")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got: %v", err)
+	}
+}
+
+func TestResourceOf(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		url      string
+		expected string
+	}{
+		{url: "https://api.github.com/repos/owner/repo", expected: resourceCore},
+		{url: "https://api.github.com/search/code?q=foo", expected: resourceSearch},
+		{url: "https://github.example.com/api/v3/search/code?q=foo", expected: resourceSearch},
+		{url: "https://api.github.com/graphql", expected: resourceGraphQL},
+		{url: "https://github.example.com/api/graphql", expected: resourceGraphQL},
+	}
+	for _, tt := range testcases {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		if got := resourceOf(req); got != tt.expected {
+			t.Errorf("resourceOf(%s): expected %s, got %s", tt.url, tt.expected, got)
+		}
+	}
+}
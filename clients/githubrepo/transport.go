@@ -17,18 +17,16 @@ package githubrepo
 import (
 	"fmt"
 	"net/http"
-	"strconv"
-	"sync/atomic"
-
-	"go.opencensus.io/stats"
-	"go.opencensus.io/tag"
 )
 
 // MakeGitHubTransport wraps input RoundTripper with GitHub authorization logic.
+// Each request uses the token with the most remaining rate limit for the requested
+// resource, and is retried with another token if the chosen one turns out to be exhausted.
 func MakeGitHubTransport(innerTransport http.RoundTripper, accessTokens []string) http.RoundTripper {
 	return &githubTransport{
 		innerTransport: innerTransport,
 		tokens:         makeTokenAccessor(accessTokens),
+		maxAttempts:    len(accessTokens) + 1,
 	}
 }
 
@@ -36,54 +34,42 @@ func MakeGitHubTransport(innerTransport http.RoundTripper, accessTokens []string
 type githubTransport struct {
 	innerTransport http.RoundTripper
 	tokens         tokenAccessor
+	maxAttempts    int
 }
 
 type tokenAccessor interface {
-	next(r *http.Request) (string, error)
+	// next returns the index of the token to use for r along with the token itself.
+	next(r *http.Request) (int, string, error)
+	// update records the rate limit of the token at index, as reported by resp.
+	update(index int, r *http.Request, resp *http.Response)
 }
 
 func (gt *githubTransport) RoundTrip(r *http.Request) (*http.Response, error) {
-	token, err := gt.tokens.next(r)
-	if err != nil {
-		return nil, fmt.Errorf("error getting Github token: %w", err)
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
-	resp, err := gt.innerTransport.RoundTrip(r)
-	if err != nil {
-		return nil, fmt.Errorf("error in HTTP: %w", err)
-	}
-	ctx, err := tag.New(r.Context(), tag.Upsert(ResourceType, resp.Header.Get("X-RateLimit-Resource")))
-	if err != nil {
-		return nil, fmt.Errorf("error updating context: %w", err)
-	}
-	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
-	if err == nil {
-		stats.Record(ctx, RemainingTokens.M(int64(remaining)))
-	}
-	return resp, nil
-}
+	for attempt := 1; ; attempt++ {
+		req := r.Clone(r.Context())
+		if r.Body != nil && r.GetBody != nil && attempt > 1 {
+			body, err := r.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("error rewinding request body: %w", err)
+			}
+			req.Body = body
+		}
+		index, token, err := gt.tokens.next(req)
+		if err != nil {
+			return nil, fmt.Errorf("error getting Github token: %w", err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		resp, err := gt.innerTransport.RoundTrip(req)
+		if err != nil {
+			return nil, fmt.Errorf("error in HTTP: %w", err)
+		}
+		gt.tokens.update(index, req, resp)
 
-func makeTokenAccessor(accessTokens []string) tokenAccessor {
-	return &roundRobinAccessor{
-		accessTokens: accessTokens,
+		// Requests without a rewindable body cannot be retried.
+		canRetry := r.Body == nil || r.GetBody != nil
+		if !isRateLimited(resp) || !canRetry || attempt >= gt.maxAttempts {
+			return resp, nil
+		}
+		resp.Body.Close()
 	}
 }
-
-type roundRobinAccessor struct {
-	accessTokens []string
-	counter      uint64
-}
-
-func (roundRobin *roundRobinAccessor) next(r *http.Request) (string, error) {
-	c := atomic.AddUint64(&roundRobin.counter, 1)
-	l := len(roundRobin.accessTokens)
-	index := c % uint64(l)
-
-	ctx, err := tag.New(r.Context(), tag.Upsert(TokenIndex, fmt.Sprint(index)))
-	if err != nil {
-		return "", fmt.Errorf("error updating context: %w", err)
-	}
-	*r = *r.WithContext(ctx)
-
-	return roundRobin.accessTokens[index], nil
-}
@@ -0,0 +1,91 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/clients/githubrepo"
+	docs "github.com/ossf/scorecard/v2/docs/checks"
+	"github.com/ossf/scorecard/v2/roundtripper"
+)
+
+const anonymousReason = "check requires GitHub credentials, which are not set. " +
+	"Please read https://github.com/ossf/scorecard#authentication"
+
+// Returns the GitHub REST and GraphQL clients for host. The GraphQL API does not allow
+// unauthenticated requests, so there is no GraphQL client when running anonymously.
+func makeGitHubClients(httpClient *http.Client, host string) (*github.Client, *githubv4.Client, error) {
+	githubClient, graphClient, err := githubrepo.NewClients(httpClient, host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating GitHub clients: %w", err)
+	}
+	if roundtripper.IsAnonymous() {
+		return githubClient, nil, nil
+	}
+	return githubClient, graphClient, nil
+}
+
+// Replaces the enabled checks which cannot run without credentials, as listed in
+// checks.yaml, by ones which are always inconclusive.
+func disableChecksRequiringAuth(enabledChecks checker.CheckNameToFnMap) checker.CheckNameToFnMap {
+	doc, err := docs.Read()
+	if err != nil {
+		log.Fatal(err)
+	}
+	ret := checker.CheckNameToFnMap{}
+	disabled := []string{}
+	for name, fn := range enabledChecks {
+		if check, ok := doc.Checks[name]; ok && check.RequiresAuth() {
+			name := name
+			ret[name] = func(c *checker.CheckRequest) checker.CheckResult {
+				return checker.CreateInconclusiveResult(name, anonymousReason)
+			}
+			disabled = append(disabled, name)
+			continue
+		}
+		ret[name] = fn
+	}
+	if len(disabled) > 0 {
+		sort.Strings(disabled)
+		fmt.Fprintf(os.Stderr, "GitHub credentials are not set, skipping checks: %s\n", strings.Join(disabled, ", "))
+	}
+	return ret
+}
+
+// Prints the remaining unauthenticated rate limit budget when running anonymously.
+func printRemainingBudget(ctx context.Context, githubClient *github.Client) {
+	if !roundtripper.IsAnonymous() {
+		return
+	}
+	// Querying the rate limit does not count against it.
+	limits, _, err := githubClient.RateLimits(ctx)
+	if err != nil || limits.GetCore() == nil {
+		return
+	}
+	core := limits.GetCore()
+	fmt.Fprintf(os.Stderr, "Remaining unauthenticated GitHub API budget: %d of %d requests, resets at %s\n",
+		core.Remaining, core.Limit, core.Reset.Format("15:04:05 MST"))
+}
@@ -0,0 +1,204 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/google/go-github/v32/github"
+	"go.uber.org/zap"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/clients"
+	"github.com/ossf/scorecard/v2/clients/githubrepo"
+	"github.com/ossf/scorecard/v2/cron/data"
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+var errBatchFailures = errors.New("failed to check some repositories")
+
+// batchFailure records a repository which could not be checked.
+type batchFailure struct {
+	repo string
+	err  error
+}
+
+// Reads the repositories listed in `--repos-file`. Invalid entries are returned
+// as failures instead of aborting the whole batch.
+func readReposFile(filename string) ([]repos.RepoURL, []batchFailure, error) {
+	// nolint: gomnd
+	in, err := os.OpenFile(filename, os.O_RDONLY, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening repos file: %w", err)
+	}
+	defer in.Close()
+
+	iter, err := data.MakeIteratorFrom(in)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error during data.MakeIteratorFrom: %w", err)
+	}
+	repoURLs := []repos.RepoURL{}
+	failures := []batchFailure{}
+	for entry := 1; iter.HasNext(); entry++ {
+		repoURL, err := iter.Next()
+		if err != nil {
+			name := fmt.Sprintf("%s entry #%d", filename, entry)
+			if repoURL.Host != "" {
+				name = repoURL.URL()
+			}
+			failures = append(failures, batchFailure{repo: name, err: err})
+			continue
+		}
+		repoURLs = append(repoURLs, repoURL)
+	}
+	return repoURLs, failures, nil
+}
+
+// Runs the enabled checks on all repositories listed in `--repos-file`.
+func runBatch(ctx context.Context, logger *zap.SugaredLogger, enabledChecks checker.CheckNameToFnMap) error {
+	repoURLs, failures, err := readReposFile(reposFile)
+	if err != nil {
+		return err
+	}
+	total := len(repoURLs) + len(failures)
+
+	results, batchFailures := scoreRepos(ctx, logger, repoURLs, enabledChecks)
+	failures = append(failures, batchFailures...)
+
+	if err := outputBatchResults(results); err != nil {
+		return fmt.Errorf("failed to output results: %w", err)
+	}
+	return reportBatchFailures(failures, total)
+}
+
+// Runs the enabled checks on repoURLs, sharing a single HTTP transport across a
+// bounded pool of `--parallelism` workers. Repos on different hosts need different
+// clients, so they are checked in one batch per host and results are grouped by host,
+// in order of first appearance. Results have their checks sorted by name.
+func scoreRepos(ctx context.Context, logger *zap.SugaredLogger,
+	repoURLs []repos.RepoURL, enabledChecks checker.CheckNameToFnMap) ([]pkg.ScorecardResult, []batchFailure) {
+	rt := newTransport(ctx, logger)
+	httpClient := &http.Client{
+		Transport: rt,
+	}
+	hosts := []string{}
+	reposByHost := make(map[string][]repos.RepoURL)
+	for _, repoURL := range repoURLs {
+		if _, ok := reposByHost[repoURL.Host]; !ok {
+			hosts = append(hosts, repoURL.Host)
+		}
+		reposByHost[repoURL.Host] = append(reposByHost[repoURL.Host], repoURL)
+	}
+
+	logger.Infof("Checking %d repositories with parallelism %d", len(repoURLs), parallelism)
+	results := make([]pkg.ScorecardResult, 0, len(repoURLs))
+	failures := []batchFailure{}
+	for _, host := range hosts {
+		hostRepos := reposByHost[host]
+		githubClient, graphClient, err := makeGitHubClients(httpClient, host)
+		if err != nil {
+			for i := range hostRepos {
+				failures = append(failures, batchFailure{repo: hostRepos[i].URL(), err: err})
+			}
+			continue
+		}
+		if resolveRedirects {
+			hostRepos, failures = resolveRepoRedirects(ctx, githubClient, hostRepos, failures)
+		}
+		newRepoClient := func() clients.RepoClient {
+			return githubrepo.CreateGithubRepoClient(ctx, githubClient, graphClient)
+		}
+		batchResults := pkg.RunScorecardsBatch(ctx, hostRepos, enabledChecks, parallelism, newRepoClient,
+			httpClient, githubClient, graphClient)
+
+		for i := range batchResults {
+			batchResult := &batchResults[i]
+			if batchResult.Err != nil {
+				failures = append(failures, batchFailure{repo: batchResult.Repo.URL(), err: batchResult.Err})
+				continue
+			}
+			result := batchResult.Result
+			result.Metadata = append(result.Metadata, metaData...)
+			sort.Slice(result.Checks, func(i, j int) bool {
+				return result.Checks[i].Name < result.Checks[j].Name
+			})
+			results = append(results, result)
+		}
+		printRemainingBudget(ctx, githubClient)
+	}
+	return results, failures
+}
+
+// Resolves the current names of repoURLs, moving the repos which could not be resolved to failures.
+func resolveRepoRedirects(ctx context.Context, githubClient *github.Client,
+	repoURLs []repos.RepoURL, failures []batchFailure) ([]repos.RepoURL, []batchFailure) {
+	resolved := make([]repos.RepoURL, 0, len(repoURLs))
+	for i := range repoURLs {
+		repoURL := repoURLs[i]
+		if err := githubrepo.ResolveRedirects(ctx, githubClient, &repoURL); err != nil {
+			failures = append(failures, batchFailure{repo: repoURL.URL(), err: err})
+			continue
+		}
+		resolved = append(resolved, repoURL)
+	}
+	return resolved, failures
+}
+
+// Prints a summary of the repositories which could not be checked and returns an error if there were any.
+func reportBatchFailures(failures []batchFailure, total int) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "\nFailed to check %d of %d repositories:\n", len(failures), total)
+	for _, failure := range failures {
+		fmt.Fprintf(os.Stderr, "  %s: %v\n", failure.repo, failure.err)
+	}
+	return fmt.Errorf("%w: %d of %d", errBatchFailures, len(failures), total)
+}
+
+func outputBatchResults(results []pkg.ScorecardResult) error {
+	switch format {
+	case formatDefault:
+		for i := range results {
+			fmt.Printf("\nRESULTS for %s\n-------\n", results[i].Repo)
+			if err := results[i].AsString(showDetails, *logLevel, os.Stdout); err != nil {
+				return fmt.Errorf("error during AsString: %w", err)
+			}
+		}
+	case formatCSV:
+		if err := pkg.ResultsAsCSV(results, showDetails, *logLevel, os.Stdout); err != nil {
+			return fmt.Errorf("error during ResultsAsCSV: %w", err)
+		}
+	case formatJSON:
+		// One JSON object per line, i.e. NDJSON.
+		for i := range results {
+			if err := results[i].AsJSON(showDetails, *logLevel, os.Stdout); err != nil {
+				return fmt.Errorf("error during AsJSON: %w", err)
+			}
+		}
+	default:
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal,
+			fmt.Sprintf("invalid format flag: %v. Expected [default, csv, json]", format))
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	docs "github.com/ossf/scorecard/v2/docs/checks"
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+var errUnknownCheck = errors.New("unknown check")
+
+// checksFormat is the `--format` of the checks commands, which is separate from the
+// root command's so that their defaults and allowed values do not interfere.
+var checksFormat string
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(checksCmd)
+	checksCmd.AddCommand(checksListCmd, checksExplainCmd)
+	checksCmd.PersistentFlags().StringVar(&checksFormat, "format", formatDefault,
+		"output format. allowed values are [default, json]")
+}
+
+var checksCmd = &cobra.Command{
+	Use:   "checks",
+	Short: "Describe the available checks",
+	Long:  ``,
+}
+
+var checksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all checks with their risk and requirements",
+	Long:  ``,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		doc, err := docs.Read()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := listChecks(doc, checksFormat, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var checksExplainCmd = &cobra.Command{
+	Use:   "explain <check>",
+	Short: "Print the description and remediation steps of a check",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		doc, err := docs.Read()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := explainCheck(doc, args[0], checksFormat, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// checkSummary is the JSON output of `checks list` for a single check.
+type checkSummary struct {
+	Name               string
+	Risk               string
+	RequiresAdminToken bool
+	RequiresSearchAPI  bool
+	RequiresGraphQL    bool
+}
+
+// checkExplanation is the JSON output of `checks explain`.
+type checkExplanation struct {
+	Name string
+	docs.Check
+}
+
+func summarizeCheck(name string, check *docs.Check) checkSummary {
+	summary := checkSummary{
+		Name: name,
+		Risk: check.Risk,
+	}
+	for _, req := range check.Requires {
+		switch req {
+		case docs.RequiresAdminToken:
+			summary.RequiresAdminToken = true
+		case docs.RequiresSearchAPI:
+			summary.RequiresSearchAPI = true
+		case docs.RequiresGraphQL:
+			summary.RequiresGraphQL = true
+		}
+	}
+	return summary
+}
+
+func listChecks(doc docs.Doc, format string, writer io.Writer) error {
+	names := make([]string, 0, len(doc.Checks))
+	for name := range doc.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	summaries := make([]checkSummary, 0, len(names))
+	for _, name := range names {
+		check := doc.Checks[name]
+		summaries = append(summaries, summarizeCheck(name, &check))
+	}
+
+	switch format {
+	case formatDefault:
+		table := tablewriter.NewWriter(writer)
+		table.SetHeader([]string{"Name", "Risk", "Admin Token", "Search API", "GraphQL"})
+		for _, summary := range summaries {
+			table.Append([]string{
+				summary.Name,
+				summary.Risk,
+				yesNo(summary.RequiresAdminToken),
+				yesNo(summary.RequiresSearchAPI),
+				yesNo(summary.RequiresGraphQL),
+			})
+		}
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.Render()
+	case formatJSON:
+		if err := json.NewEncoder(writer).Encode(summaries); err != nil {
+			//nolint:wrapcheck
+			return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("encoder.Encode: %v", err))
+		}
+	default:
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal,
+			fmt.Sprintf("invalid format flag: %v. Expected [default, json]", format))
+	}
+	return nil
+}
+
+func explainCheck(doc docs.Doc, checkName, format string, writer io.Writer) error {
+	var explanation *checkExplanation
+	for name, check := range doc.Checks {
+		if strings.EqualFold(name, checkName) {
+			explanation = &checkExplanation{Name: name, Check: check}
+			break
+		}
+	}
+	if explanation == nil {
+		return fmt.Errorf("%w: %s", errUnknownCheck, checkName)
+	}
+
+	switch format {
+	case formatDefault:
+		summary := summarizeCheck(explanation.Name, &explanation.Check)
+		fmt.Fprintf(writer, "%s\n\n", explanation.Name)
+		fmt.Fprintf(writer, "Risk: %s\n", summary.Risk)
+		fmt.Fprintf(writer, "Requires admin token: %s\n", yesNo(summary.RequiresAdminToken))
+		fmt.Fprintf(writer, "Requires search API: %s\n", yesNo(summary.RequiresSearchAPI))
+		fmt.Fprintf(writer, "Requires GraphQL: %s\n\n", yesNo(summary.RequiresGraphQL))
+		fmt.Fprintf(writer, "%s\n\nRemediation steps:\n", explanation.Description)
+		for _, step := range explanation.Remediation {
+			fmt.Fprintf(writer, "- %s\n", step)
+		}
+	case formatJSON:
+		if err := json.NewEncoder(writer).Encode(explanation); err != nil {
+			//nolint:wrapcheck
+			return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("encoder.Encode: %v", err))
+		}
+	default:
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal,
+			fmt.Sprintf("invalid format flag: %v. Expected [default, json]", format))
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,171 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	docs "github.com/ossf/scorecard/v2/docs/checks"
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+var testDoc = docs.Doc{
+	Checks: map[string]docs.Check{
+		"Fuzzing": {
+			Risk:        "Medium",
+			Description: "Checks fuzzing.",
+			Remediation: []string{"Fuzz."},
+			Requires:    []string{docs.RequiresSearchAPI},
+		},
+		"Branch-Protection": {
+			Risk:        "High",
+			Description: "Checks branch protection.",
+			Remediation: []string{"Protect.", "Review."},
+			Requires:    []string{docs.RequiresAdminToken, docs.RequiresGraphQL},
+		},
+	},
+}
+
+func TestListChecks(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "default",
+			format: formatDefault,
+			want: "|-------------------|--------|-------------|------------|---------|\n" +
+				"|       Name        |  Risk  | Admin Token | Search API | GraphQL |\n" +
+				"|-------------------|--------|-------------|------------|---------|\n" +
+				"| Branch-Protection | High   | yes         | no         | yes     |\n" +
+				"| Fuzzing           | Medium | no          | yes        | no      |\n" +
+				"|-------------------|--------|-------------|------------|---------|\n",
+		},
+		{
+			name:   "json",
+			format: formatJSON,
+			want: `[{"Name":"Branch-Protection","Risk":"High","RequiresAdminToken":true,` +
+				`"RequiresSearchAPI":false,"RequiresGraphQL":true},` +
+				`{"Name":"Fuzzing","Risk":"Medium","RequiresAdminToken":false,` +
+				`"RequiresSearchAPI":true,"RequiresGraphQL":false}]` + "\n",
+		},
+		{
+			name:    "invalid format",
+			format:  "csv",
+			wantErr: sce.ErrScorecardInternal,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Re-initializing variable so it is not changed while executing the closure below
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			err := listChecks(testDoc, tt.format, &buf)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("listChecks error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); tt.wantErr == nil && diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExplainCheck(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		check   string
+		format  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "default",
+			check:  "branch-protection",
+			format: formatDefault,
+			want: "Branch-Protection\n\n" +
+				"Risk: High\n" +
+				"Requires admin token: yes\n" +
+				"Requires search API: no\n" +
+				"Requires GraphQL: yes\n\n" +
+				"Checks branch protection.\n\n" +
+				"Remediation steps:\n" +
+				"- Protect.\n" +
+				"- Review.\n",
+		},
+		{
+			name:   "json",
+			check:  "Fuzzing",
+			format: formatJSON,
+			want: `{"Name":"Fuzzing","Risk":"Medium","Description":"Checks fuzzing.",` +
+				`"Remediation":["Fuzz."],"Requires":["search-api"]}` + "\n",
+		},
+		{
+			name:    "unknown check",
+			check:   "Unknown",
+			format:  formatDefault,
+			wantErr: errUnknownCheck,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Re-initializing variable so it is not changed while executing the closure below
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			err := explainCheck(testDoc, tt.check, tt.format, &buf)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("explainCheck error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); tt.wantErr == nil && diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestChecksYAMLRisk checks that the risk of every check is read from checks.yaml, and
+// output as JSON.
+func TestChecksYAMLRisk(t *testing.T) {
+	t.Parallel()
+	doc, err := docs.Read()
+	if err != nil {
+		t.Fatalf("docs.Read: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := listChecks(doc, formatJSON, &buf); err != nil {
+		t.Fatalf("listChecks: %v", err)
+	}
+	var summaries []checkSummary
+	if err := json.Unmarshal(buf.Bytes(), &summaries); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if len(summaries) != len(doc.Checks) {
+		t.Errorf("listed %d checks, want %d", len(summaries), len(doc.Checks))
+	}
+	for _, summary := range summaries {
+		if summary.Risk == "" {
+			t.Errorf("%s: missing risk", summary.Name)
+		}
+	}
+}
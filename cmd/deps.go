@@ -0,0 +1,227 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/checks"
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/manifests"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+	"github.com/ossf/scorecard/v2/resolvers"
+)
+
+var manifest string
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(depsCmd)
+	depsCmd.Flags().StringVar(&manifest, "manifest", "",
+		fmt.Sprintf("manifest whose dependencies to check. supported files are [%s]",
+			strings.Join(manifests.Names(), ", ")))
+	depsCmd.Flags().IntVar(
+		&parallelism, "parallelism", defaultParallelism,
+		"number of dependencies to resolve and check concurrently")
+	depsCmd.Flags().StringVar(&format, "format", formatDefault, "output format. allowed values are [default, csv, json]")
+	depsCmd.Flags().BoolVar(&showDetails, "show-details", false, "show extra details about each check")
+	checkNames := []string{}
+	for checkName := range checks.AllChecks {
+		checkNames = append(checkNames, checkName)
+	}
+	depsCmd.Flags().StringSliceVar(&checksToRun, "checks", []string{},
+		fmt.Sprintf("Checks to run. Possible values are: %s", strings.Join(checkNames, ",")))
+	if err := depsCmd.MarkFlagRequired("manifest"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+var depsCmd = &cobra.Command{
+	Use:   "deps --manifest=<go.mod|modules.txt|package-lock.json|requirements.txt|Cargo.lock>",
+	Short: "Check the dependencies of a manifest",
+	Long: `Parses the manifest, resolves each dependency to its source repository
+and checks all of them, reporting per-dependency results, aggregate statistics
+and the dependencies which could not be resolved.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := zap.NewProductionConfig()
+		cfg.Level.SetLevel(*logLevel)
+		logger, err := cfg.Build()
+		if err != nil {
+			log.Fatalf("unable to construct logger: %v", err)
+		}
+		// nolint
+		defer logger.Sync() // flushes buffer, if any
+		sugar := logger.Sugar()
+
+		if err := runDeps(context.Background(), sugar, getEnabledChecks()); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// resolvedDependency is a dependency along with its source repository.
+type resolvedDependency struct {
+	dep  manifests.Dependency
+	repo repos.RepoURL
+}
+
+// Runs the enabled checks on the source repositories of all dependencies of `--manifest`.
+func runDeps(ctx context.Context, logger *zap.SugaredLogger, enabledChecks checker.CheckNameToFnMap) error {
+	deps, err := manifests.ParseFile(manifest)
+	if err != nil {
+		return fmt.Errorf("error parsing manifest: %w", err)
+	}
+	logger.Infof("Resolving %d dependencies of %s", len(deps), manifest)
+	resolved, unresolved := resolveDependencies(ctx, deps)
+
+	// Dependencies often share a repository, e.g. the modules of a monorepo, so only check each one once.
+	repoURLs := []repos.RepoURL{}
+	seen := make(map[string]bool)
+	for i := range resolved {
+		if url := resolved[i].repo.URL(); !seen[url] {
+			seen[url] = true
+			repoURLs = append(repoURLs, resolved[i].repo)
+		}
+	}
+	results, failures := scoreRepos(ctx, logger, repoURLs, enabledChecks)
+
+	resultsByRepo := make(map[string]pkg.ScorecardResult, len(results))
+	for i := range results {
+		resultsByRepo[results[i].Repo] = results[i]
+	}
+	failuresByRepo := make(map[string]error, len(failures))
+	for _, failure := range failures {
+		failuresByRepo[failure.repo] = failure.err
+	}
+	depsResult := pkg.DependenciesResult{
+		Manifest:     manifest,
+		Date:         time.Now().Format("2006-01-02"),
+		Dependencies: []pkg.DependencyResult{},
+		Unresolved:   unresolved,
+		Rollup:       pkg.RollupResults(results, defaultWorstOffenders),
+	}
+	for i := range resolved {
+		dep := &resolved[i].dep
+		url := resolved[i].repo.URL()
+		result, ok := resultsByRepo[url]
+		if !ok {
+			depsResult.Unresolved = append(depsResult.Unresolved, unresolvedDependency(dep,
+				fmt.Sprintf("failed to check %s: %v", url, failuresByRepo[url])))
+			continue
+		}
+		depsResult.Dependencies = append(depsResult.Dependencies, pkg.DependencyResult{
+			Ecosystem: dep.Ecosystem,
+			Name:      dep.Name,
+			Version:   dep.Version,
+			Result:    result,
+		})
+	}
+
+	switch format {
+	case formatDefault:
+		fmt.Printf("\nRESULTS for %s\n-------\n", manifest)
+		err = depsResult.AsString(showDetails, *logLevel, os.Stdout)
+	case formatCSV:
+		err = depsResult.AsCSV(showDetails, *logLevel, os.Stdout)
+	case formatJSON:
+		err = depsResult.AsJSON(showDetails, *logLevel, os.Stdout)
+	default:
+		err = sce.Create(sce.ErrScorecardInternal,
+			fmt.Sprintf("invalid format flag: %v. Expected [default, csv, json]", format))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to output results: %w", err)
+	}
+	return reportBatchFailures(failures, len(repoURLs))
+}
+
+// Resolves the source repository of each dependency using a pool of `--parallelism` workers.
+// Dependencies which do not resolve to a GitHub repository are returned as unresolved.
+func resolveDependencies(ctx context.Context,
+	deps []manifests.Dependency) ([]resolvedDependency, []pkg.UnresolvedDependency) {
+	client := &http.Client{
+		Timeout: resolverTimeout,
+	}
+	repoURLs := make([]repos.RepoURL, len(deps))
+	errs := make([]error, len(deps))
+
+	indices := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				repoURLs[i], errs[i] = resolveDependency(ctx, client, &deps[i])
+			}
+		}()
+	}
+	for i := range deps {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	resolved := []resolvedDependency{}
+	unresolved := []pkg.UnresolvedDependency{}
+	for i := range deps {
+		if errs[i] != nil {
+			unresolved = append(unresolved, unresolvedDependency(&deps[i], errs[i].Error()))
+			continue
+		}
+		resolved = append(resolved, resolvedDependency{dep: deps[i], repo: repoURLs[i]})
+	}
+	return resolved, unresolved
+}
+
+func resolveDependency(ctx context.Context, client *http.Client, dep *manifests.Dependency) (repos.RepoURL, error) {
+	repoURL := repos.RepoURL{}
+	resolver, err := resolvers.Get(dep.Ecosystem, client)
+	if err != nil {
+		return repoURL, fmt.Errorf("error getting resolver: %w", err)
+	}
+	git, err := resolver.Resolve(ctx, dep.Name)
+	if err != nil {
+		return repoURL, fmt.Errorf("error resolving package: %w", err)
+	}
+	if err := repoURL.Set(git); err != nil {
+		return repoURL, fmt.Errorf("error during repo.Set: %w", err)
+	}
+	if err := repoURL.ValidGitHubURL(); err != nil {
+		return repoURL, fmt.Errorf("error during ValidGitHubURL: %w", err)
+	}
+	return repoURL, nil
+}
+
+func unresolvedDependency(dep *manifests.Dependency, reason string) pkg.UnresolvedDependency {
+	return pkg.UnresolvedDependency{
+		Ecosystem: dep.Ecosystem,
+		Name:      dep.Name,
+		Version:   dep.Version,
+		Reason:    reason,
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/ossf/scorecard/v2/server"
+)
+
+//nolint:gochecknoinits
+func init() {
+	grpcCmd.Flags().DurationVar(&resultCacheTTL, "result-cache-ttl", defaultResultCacheTTL,
+		"how long results are cached for each HEAD commit of a repo. 0 disables the cache")
+	grpcCmd.Flags().StringVar(&resultCacheDir, "result-cache-dir", "",
+		"directory to also cache results in, so that they survive restarts")
+	grpcCmd.Flags().DurationVar(&scanTimeout, "scan-timeout", defaultScanTimeout,
+		"how long a scan may run before it fails")
+	grpcCmd.Flags().StringVar(&apiKeysFile, "api-keys-file", "",
+		"YAML file of the API keys required to call the service, and their quotas")
+	rootCmd.AddCommand(grpcCmd)
+}
+
+const defaultGRPCPort = "50051"
+
+var grpcCmd = &cobra.Command{
+	Use:   "grpc",
+	Short: "Serve the scorecard program over gRPC",
+	Long: `Serve the scorecard program over gRPC.
+
+Serves the ossf.scorecard.cron.data.Scorecard service defined in
+cron/data/scorecard.proto on the port set by the PORT environment variable,
+50051 by default. Score returns the result of a repo, and ScoreStream sends the
+result of each check as soon as it completes. Results are cached as in serve.
+The standard health and reflection services are also served.
+
+If --api-keys-file is set, calls must send one of its keys as a bearer token in
+the authorization metadata or in x-api-key, and are limited to its quota.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := zap.NewProductionConfig()
+		cfg.Level.SetLevel(*logLevel)
+		logger, err := cfg.Build()
+		if err != nil {
+			log.Fatalf("unable to construct logger: %v", err)
+		}
+		//nolint
+		defer logger.Sync() // flushes buffer, if any
+		sugar := logger.Sugar()
+
+		httpClient := &http.Client{
+			Transport: newTransport(context.Background(), sugar),
+		}
+		opts, err := makeServerOptions(sugar, httpClient)
+		if err != nil {
+			sugar.Panic(err)
+		}
+		// The HEAD commit is needed to accept requests for it by SHA.
+		opts.Head = makeHeadFunc(httpClient)
+		srv, err := server.New(opts)
+		if err != nil {
+			sugar.Panic(err)
+		}
+		defer srv.Close()
+
+		port := os.Getenv("PORT")
+		if port == "" {
+			port = defaultGRPCPort
+		}
+		listener, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%s", port))
+		if err != nil {
+			log.Fatal("Listen ", err)
+		}
+		fmt.Printf("Listening on localhost:%s\n", port)
+		if err := srv.NewGRPCServer().Serve(listener); err != nil {
+			log.Fatal("Serve ", err)
+		}
+	},
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/clients/githubrepo"
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/pkg"
+)
+
+const defaultWorstOffenders = 5
+
+var (
+	org       string
+	orgFilter githubrepo.OrgRepoFilter
+)
+
+// Runs the enabled checks on all repositories of `--org` which match the filter flags,
+// and outputs per-repo results along with an org-wide rollup.
+func runOrg(ctx context.Context, logger *zap.SugaredLogger, enabledChecks checker.CheckNameToFnMap) error {
+	httpClient := &http.Client{
+		Transport: newTransport(ctx, logger),
+	}
+	host, orgName := parseOrg(org)
+	githubClient, _, err := makeGitHubClients(httpClient, host)
+	if err != nil {
+		return err
+	}
+	repoURLs, err := githubrepo.ListOrgRepos(ctx, githubClient, host, orgName, orgFilter)
+	if err != nil {
+		return fmt.Errorf("error listing repositories for %s: %w", org, err)
+	}
+
+	results, failures := scoreRepos(ctx, logger, repoURLs, enabledChecks)
+	orgResult := pkg.OrgResult{
+		Org:    org,
+		Date:   time.Now().Format("2006-01-02"),
+		Repos:  results,
+		Rollup: pkg.RollupResults(results, defaultWorstOffenders),
+	}
+
+	switch format {
+	case formatDefault:
+		fmt.Printf("\nRESULTS for %s\n-------\n", org)
+		err = orgResult.AsString(showDetails, *logLevel, os.Stdout)
+	case formatCSV:
+		err = pkg.ResultsAsCSV(results, showDetails, *logLevel, os.Stdout)
+	case formatJSON:
+		err = orgResult.AsJSON(showDetails, *logLevel, os.Stdout)
+	default:
+		err = sce.Create(sce.ErrScorecardInternal,
+			fmt.Sprintf("invalid format flag: %v. Expected [default, csv, json]", format))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to output results: %w", err)
+	}
+	return reportBatchFailures(failures, len(repoURLs))
+}
+
+// Returns the host and name of `--org`, which is either `<org>` on github.com or
+// `<host>/<org>` on a GitHub Enterprise Server host.
+func parseOrg(org string) (host, name string) {
+	if i := strings.LastIndex(org, "/"); i >= 0 {
+		return strings.ToLower(org[:i]), org[i+1:]
+	}
+	return "github.com", org
+}
@@ -17,7 +17,6 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	goflag "flag"
 	"fmt"
 	"log"
@@ -27,8 +26,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/go-github/v32/github"
-	"github.com/shurcooL/githubv4"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
@@ -38,6 +35,7 @@ import (
 	sce "github.com/ossf/scorecard/v2/errors"
 	"github.com/ossf/scorecard/v2/pkg"
 	"github.com/ossf/scorecard/v2/repos"
+	"github.com/ossf/scorecard/v2/resolvers"
 	"github.com/ossf/scorecard/v2/roundtripper"
 )
 
@@ -48,23 +46,52 @@ var (
 	// This one has to use goflag instead of pflag because it's defined by zap.
 	logLevel    = zap.LevelFlag("verbosity", zap.InfoLevel, "override the default log level")
 	format      string
-	npm         string
-	pypi        string
-	rubygems    string
 	showDetails bool
+	reposFile   string
+	parallelism int
+	// packages maps each resolvers ecosystem to the package name passed with its flag.
+	packages = map[string]*string{}
+
+	githubHostsFile string
+	githubAPIURL    string
+	cacheDir        string
+	cacheTTL        time.Duration
+
+	resolveRedirects bool
 )
 
 const (
 	formatCSV     = "csv"
 	formatJSON    = "json"
 	formatDefault = "default"
+	formatSVG     = "svg"
+
+	defaultParallelism = 5
+
+	resolverTimeout = 10 * time.Second
+
+	defaultCacheTTL = 24 * time.Hour
 )
 
 var rootCmd = &cobra.Command{
 	Use: `./scorecard --repo=<repo_url> [--checks=check1,...] [--show-details]
-or ./scorecard --{npm,pypi,rubgems}=<package_name> [--checks=check1,...] [--show-details]`,
+or ./scorecard --{npm,pypi,rubygems,crates,go,maven,nuget,packagist}=<package_name> [--checks=check1,...] [--show-details]
+or ./scorecard --repos-file=<csv_file> [--parallelism=N] [--checks=check1,...] [--show-details]
+or ./scorecard --org=<owner> [--parallelism=N] [--checks=check1,...] [--show-details]`,
 	Short: "Security Scorecards",
 	Long:  "A program that shows security scorecard for an open source software.",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if githubHostsFile != "" {
+			if err := githubrepo.LoadHosts(githubHostsFile); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if githubAPIURL != "" {
+			if err := githubrepo.SetGitHubAPIURL(githubAPIURL); err != nil {
+				log.Fatal(err)
+			}
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := zap.NewProductionConfig()
 		cfg.Level.SetLevel(*logLevel)
@@ -76,29 +103,33 @@ or ./scorecard --{npm,pypi,rubgems}=<package_name> [--checks=check1,...] [--show
 		defer logger.Sync() // flushes buffer, if any
 		sugar := logger.Sugar()
 
-		if npm != "" {
-			if git, err := fetchGitRepositoryFromNPM(npm); err != nil {
+		enabledChecks := getEnabledChecks()
+
+		if reposFile != "" && org != "" {
+			log.Fatal("--repos-file cannot be used with --org")
+		}
+		if reposFile != "" || org != "" {
+			if ecosystem, _ := getPackageFlag(); repo.Host != "" || ecosystem != "" {
+				log.Fatal("--repos-file and --org cannot be used with --repo or a package flag")
+			}
+			run := runBatch
+			if org != "" {
+				run = runOrg
+			}
+			if err := run(context.Background(), sugar, enabledChecks); err != nil {
 				log.Fatal(err)
-			} else {
-				if err := cmd.Flags().Set("repo", git); err != nil {
-					log.Fatal(err)
-				}
 			}
-		} else if pypi != "" {
-			if git, err := fetchGitRepositoryFromPYPI(pypi); err != nil {
+			return
+		}
+
+		ecosystem, packageName := getPackageFlag()
+		if ecosystem != "" {
+			git, err := resolvePackage(context.Background(), ecosystem, packageName)
+			if err != nil {
 				log.Fatal(err)
-			} else {
-				if err := cmd.Flags().Set("repo", git); err != nil {
-					log.Fatal(err)
-				}
 			}
-		} else if rubygems != "" {
-			if git, err := fetchGitRepositoryFromRubyGems(rubygems); err != nil {
+			if err := cmd.Flags().Set("repo", git); err != nil {
 				log.Fatal(err)
-			} else {
-				if err := cmd.Flags().Set("repo", git); err != nil {
-					log.Fatal(err)
-				}
 			}
 		} else {
 			if err := cmd.MarkFlagRequired("repo"); err != nil {
@@ -110,16 +141,6 @@ or ./scorecard --{npm,pypi,rubgems}=<package_name> [--checks=check1,...] [--show
 			log.Fatal(err)
 		}
 
-		enabledChecks := checker.CheckNameToFnMap{}
-		if len(checksToRun) != 0 {
-			for _, checkToRun := range checksToRun {
-				if !enableCheck(checkToRun, &enabledChecks) {
-					log.Fatalf("Invalid check: %s", checkToRun)
-				}
-			}
-		} else {
-			enabledChecks = checks.AllChecks
-		}
 		if format == formatDefault {
 			for checkName := range enabledChecks {
 				fmt.Fprintf(os.Stderr, "Starting [%s]\n", checkName)
@@ -127,12 +148,19 @@ or ./scorecard --{npm,pypi,rubgems}=<package_name> [--checks=check1,...] [--show
 		}
 		ctx := context.Background()
 
-		rt := roundtripper.NewTransport(ctx, sugar)
+		rt := newTransport(ctx, sugar)
 		httpClient := &http.Client{
 			Transport: rt,
 		}
-		githubClient := github.NewClient(httpClient)
-		graphClient := githubv4.NewClient(httpClient)
+		githubClient, graphClient, err := makeGitHubClients(httpClient, repo.Host)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if resolveRedirects {
+			if err := githubrepo.ResolveRedirects(ctx, githubClient, &repo); err != nil {
+				log.Fatal(err)
+			}
+		}
 		repoClient := githubrepo.CreateGithubRepoClient(ctx, githubClient, graphClient)
 		defer repoClient.Close()
 
@@ -140,6 +168,8 @@ or ./scorecard --{npm,pypi,rubgems}=<package_name> [--checks=check1,...] [--show
 		if err != nil {
 			log.Fatal(err)
 		}
+		printRemainingBudget(ctx, githubClient)
+		repoResult.Metadata = append(repoResult.Metadata, repo.Metadata...)
 		repoResult.Metadata = append(repoResult.Metadata, metaData...)
 
 		// Sort them by name
@@ -162,9 +192,11 @@ or ./scorecard --{npm,pypi,rubgems}=<package_name> [--checks=check1,...] [--show
 			err = repoResult.AsCSV(showDetails, *logLevel, os.Stdout)
 		case formatJSON:
 			err = repoResult.AsJSON(showDetails, *logLevel, os.Stdout)
+		case formatSVG:
+			err = repoResult.AsSVG(os.Stdout)
 		default:
 			err = sce.Create(sce.ErrScorecardInternal,
-				fmt.Sprintf("invalid format flag: %v. Expected [default, csv, json]", format))
+				fmt.Sprintf("invalid format flag: %v. Expected [default, csv, json, svg]", format))
 		}
 		if err != nil {
 			log.Fatalf("Failed to output results: %v", err)
@@ -172,28 +204,6 @@ or ./scorecard --{npm,pypi,rubgems}=<package_name> [--checks=check1,...] [--show
 	},
 }
 
-type npmSearchResults struct {
-	Objects []struct {
-		Package struct {
-			Links struct {
-				Repository string `json:"repository"`
-			} `json:"links"`
-		} `json:"package"`
-	} `json:"objects"`
-}
-
-type pypiSearchResults struct {
-	Info struct {
-		ProjectUrls struct {
-			Source string `json:"Source"`
-		} `json:"project_urls"`
-	} `json:"info"`
-}
-
-type rubyGemsSearchResults struct {
-	SourceCodeURI string `json:"source_code_uri"`
-}
-
 // Execute runs the Scorecard commandline.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
@@ -202,90 +212,53 @@ func Execute() {
 	}
 }
 
-// Gets the GitHub repository URL for the npm package.
-//nolint:noctx
-func fetchGitRepositoryFromNPM(packageName string) (string, error) {
-	npmSearchURL := "https://registry.npmjs.org/-/v1/search?text=%s&size=1"
-	const timeout = 10
-	client := &http.Client{
-		Timeout: timeout * time.Second,
-	}
-	resp, err := client.Get(fmt.Sprintf(npmSearchURL, packageName))
-	if err != nil {
-		//nolint:wrapcheck
-		return "", sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("failed to get npm package json: %v", err))
-	}
-
-	defer resp.Body.Close()
-	v := &npmSearchResults{}
-	err = json.NewDecoder(resp.Body).Decode(v)
-	if err != nil {
-		//nolint:wrapcheck
-		return "", sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("failed to parse npm package json: %v", err))
-	}
-	if len(v.Objects) == 0 {
-		//nolint:wrapcheck
-		return "", sce.Create(sce.ErrScorecardInternal,
-			fmt.Sprintf("could not find source repo for npm package: %s", packageName))
+// Returns the ecosystem and name of the package passed with a package flag, if any.
+func getPackageFlag() (string, string) {
+	for _, ecosystem := range resolvers.Ecosystems() {
+		if name := *packages[ecosystem]; name != "" {
+			return ecosystem, name
+		}
 	}
-	return v.Objects[0].Package.Links.Repository, nil
+	return "", ""
 }
 
-// Gets the GitHub repository URL for the pypi package.
-//nolint:noctx
-func fetchGitRepositoryFromPYPI(packageName string) (string, error) {
-	pypiSearchURL := "https://pypi.org/pypi/%s/json"
-	const timeout = 10
+// Gets the source repository URL for the package.
+func resolvePackage(ctx context.Context, ecosystem, packageName string) (string, error) {
 	client := &http.Client{
-		Timeout: timeout * time.Second,
+		Timeout: resolverTimeout,
 	}
-	resp, err := client.Get(fmt.Sprintf(pypiSearchURL, packageName))
+	resolver, err := resolvers.Get(ecosystem, client)
 	if err != nil {
-		//nolint:wrapcheck
-		return "", sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("failed to get pypi package json: %v", err))
+		return "", fmt.Errorf("error getting resolver: %w", err)
 	}
-
-	defer resp.Body.Close()
-	v := &pypiSearchResults{}
-	err = json.NewDecoder(resp.Body).Decode(v)
+	git, err := resolver.Resolve(ctx, packageName)
 	if err != nil {
-		//nolint:wrapcheck
-		return "", sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("failed to parse pypi package json: %v", err))
-	}
-	if v.Info.ProjectUrls.Source == "" {
-		//nolint:wrapcheck
-		return "", sce.Create(sce.ErrScorecardInternal,
-			fmt.Sprintf("could not find source repo for pypi package: %s", packageName))
+		return "", fmt.Errorf("error resolving package: %w", err)
 	}
-	return v.Info.ProjectUrls.Source, nil
+	return git, nil
 }
 
-// Gets the GitHub repository URL for the rubygems package.
-//nolint:noctx
-func fetchGitRepositoryFromRubyGems(packageName string) (string, error) {
-	rubyGemsSearchURL := "https://rubygems.org/api/v1/gems/%s.json"
-	const timeout = 10
-	client := &http.Client{
-		Timeout: timeout * time.Second,
-	}
-	resp, err := client.Get(fmt.Sprintf(rubyGemsSearchURL, packageName))
-	if err != nil {
-		//nolint:wrapcheck
-		return "", sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("failed to get ruby gem json: %v", err))
-	}
+// Returns the transport for GitHub requests, which caches responses if --cache-dir is set.
+func newTransport(ctx context.Context, logger *zap.SugaredLogger) http.RoundTripper {
+	return roundtripper.NewCachingTransport(ctx, logger, cacheDir, cacheTTL)
+}
 
-	defer resp.Body.Close()
-	v := &rubyGemsSearchResults{}
-	err = json.NewDecoder(resp.Body).Decode(v)
-	if err != nil {
-		//nolint:wrapcheck
-		return "", sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("failed to parse ruby gem json: %v", err))
+// Returns the checks selected with --checks, or all checks if none were selected.
+// Without GitHub credentials, the checks which require them are disabled.
+func getEnabledChecks() checker.CheckNameToFnMap {
+	enabledChecks := checks.AllChecks
+	if len(checksToRun) != 0 {
+		enabledChecks = checker.CheckNameToFnMap{}
+		for _, checkToRun := range checksToRun {
+			if !enableCheck(checkToRun, &enabledChecks) {
+				log.Fatalf("Invalid check: %s", checkToRun)
+			}
+		}
 	}
-	if v.SourceCodeURI == "" {
-		//nolint:wrapcheck
-		return "", sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("could not find source repo for ruby gem: %v", err))
+	if roundtripper.IsAnonymous() {
+		return disableChecksRequiringAuth(enabledChecks)
 	}
-	return v.SourceCodeURI, nil
+	return enabledChecks
 }
 
 // Enables checks by name.
@@ -305,17 +278,39 @@ func enableCheck(checkName string, enabledChecks *checker.CheckNameToFnMap) bool
 func init() {
 	// Add the zap flag manually
 	rootCmd.PersistentFlags().AddGoFlagSet(goflag.CommandLine)
+	rootCmd.PersistentFlags().StringVar(&githubHostsFile, "github-hosts", "",
+		"YAML file configuring the API endpoints of GitHub Enterprise Server hosts")
+	rootCmd.PersistentFlags().StringVar(&githubAPIURL, "github-api-url", "",
+		"base URL of the GitHub API to use for github.com repos, e.g. a fake GitHub server for testing")
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "",
+		"directory to cache GitHub API responses in. responses are revalidated with their ETag")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", defaultCacheTTL,
+		"maximum age of cached GitHub API responses which have not been revalidated")
 	rootCmd.Flags().Var(&repo, "repo", "repository to check")
+	for _, ecosystem := range resolvers.Ecosystems() {
+		packages[ecosystem] = rootCmd.Flags().String(ecosystem, "",
+			fmt.Sprintf("%s package to check, given that the %s package has a GitHub repository", ecosystem, ecosystem))
+	}
 	rootCmd.Flags().StringVar(
-		&npm, "npm", "",
-		"npm package to check, given that the npm package has a GitHub repository")
-	rootCmd.Flags().StringVar(
-		&pypi, "pypi", "",
-		"pypi package to check, given that the pypi package has a GitHub repository")
-	rootCmd.Flags().StringVar(
-		&rubygems, "rubygems", "",
-		"rubygems package to check, given that the rubygems package has a GitHub repository")
-	rootCmd.Flags().StringVar(&format, "format", formatDefault, "output format. allowed values are [default, csv, json]")
+		&reposFile, "repos-file", "",
+		"CSV file of repositories to check, in the same format as cron/data/projects.csv")
+	rootCmd.Flags().IntVar(
+		&parallelism, "parallelism", defaultParallelism,
+		"number of repositories to check concurrently when using --repos-file or --org")
+	rootCmd.Flags().StringVar(&org, "org", "",
+		"GitHub organisation whose repositories to check, as <org> or <host>/<org> for GitHub Enterprise Server")
+	rootCmd.Flags().StringVar(&orgFilter.Visibility, "org-visibility", githubrepo.VisibilityAll,
+		"visibility of --org repositories to check. allowed values are [all, public, private, internal]")
+	rootCmd.Flags().StringSliceVar(&orgFilter.Topics, "org-topics", []string{},
+		"only check --org repositories with at least one of these topics")
+	rootCmd.Flags().BoolVar(&orgFilter.IncludeArchived, "org-include-archived", false,
+		"also check archived --org repositories")
+	rootCmd.Flags().BoolVar(&orgFilter.IncludeForks, "org-include-forks", false,
+		"also check forked --org repositories")
+	rootCmd.Flags().BoolVar(&resolveRedirects, "resolve-redirects", false,
+		"follow GitHub redirects to check renamed or transferred repos under their current names")
+	rootCmd.Flags().StringVar(&format, "format", formatDefault,
+		"output format. allowed values are [default, csv, json, svg]. svg outputs a badge of the aggregate score of --repo")
 	rootCmd.Flags().StringSliceVar(
 		&metaData, "metadata", []string{}, "metadata for the project.It can be multiple separated by commas")
 	rootCmd.Flags().BoolVar(&showDetails, "show-details", false, "show extra details about each check")
@@ -15,34 +15,94 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"html/template"
 	"log"
 	"net/http"
 	"os"
-	"strings"
+	"time"
 
-	"github.com/google/go-github/v32/github"
-	"github.com/shurcooL/githubv4"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
-	"github.com/ossf/scorecard/v2/checks"
+	"github.com/ossf/scorecard/v2/checker"
 	"github.com/ossf/scorecard/v2/clients/githubrepo"
+	"github.com/ossf/scorecard/v2/cron/monitoring"
+	sce "github.com/ossf/scorecard/v2/errors"
 	"github.com/ossf/scorecard/v2/pkg"
 	"github.com/ossf/scorecard/v2/repos"
-	"github.com/ossf/scorecard/v2/roundtripper"
+	"github.com/ossf/scorecard/v2/server"
 )
 
 //nolint:gochecknoinits
 func init() {
+	serveCmd.Flags().DurationVar(&resultCacheTTL, "result-cache-ttl", defaultResultCacheTTL,
+		"how long results are cached for each HEAD commit of a repo. 0 disables the cache")
+	serveCmd.Flags().StringVar(&resultCacheDir, "result-cache-dir", "",
+		"directory to also cache results in, so that they survive restarts")
+	serveCmd.Flags().StringVar(&jobOptions.Dir, "jobs-dir", "",
+		"directory to persist scan jobs in, so that they survive restarts")
+	serveCmd.Flags().IntVar(&jobOptions.Workers, "job-workers", defaultJobWorkers,
+		"number of scan jobs which run concurrently")
+	serveCmd.Flags().IntVar(&jobOptions.QueueSize, "job-queue-size", defaultJobQueueSize,
+		"number of scan jobs which may wait for a worker before new jobs are rejected")
+	serveCmd.Flags().StringVar(&historyOptions.BucketURL, "history-bucket-url", "",
+		"URL of the bucket the cron job writes results to, e.g. gs://ossf-scorecard-data or file:///path, "+
+			"to serve the results of past runs from")
+	serveCmd.Flags().DurationVar(&historyOptions.RefreshInterval, "history-refresh-interval",
+		defaultHistoryRefreshInterval, "how often results written to the history bucket are indexed")
+	serveCmd.Flags().DurationVar(&scanTimeout, "scan-timeout", defaultScanTimeout,
+		"how long a scan may run before it fails")
+	serveCmd.Flags().StringVar(&apiKeysFile, "api-keys-file", "",
+		"YAML file of the API keys required to use the API, and their quotas")
 	rootCmd.AddCommand(serveCmd)
 }
 
+const (
+	webhookSecretEnv = "GITHUB_WEBHOOK_SECRET"
+
+	defaultResultCacheTTL = 6 * time.Hour
+	defaultScanTimeout    = 10 * time.Minute
+	defaultJobWorkers     = 2
+	defaultJobQueueSize   = 100
+
+	defaultHistoryRefreshInterval = time.Hour
+)
+
+var (
+	resultCacheTTL time.Duration
+	resultCacheDir string
+	scanTimeout    time.Duration
+	jobOptions     server.JobOptions
+	historyOptions server.HistoryOptions
+	apiKeysFile    string
+)
+
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Serve the scorecard program over http",
-	Long:  ``,
+	Long: `Serve the scorecard program over http.
+
+Results are served at /api/v1/repos/{host}/{owner}/{repo}, which is described
+by the OpenAPI document at /api/v1/openapi.yaml. Scans can also be run
+asynchronously by POSTing to /api/v1/scans.
+
+If GITHUB_WEBHOOK_SECRET is set, GitHub webhook deliveries signed with it are
+accepted at /api/v1/webhooks/github, and rescore the repo on push, release and
+repository events.
+
+If --history-bucket-url is set, the results which the cron job wrote to the
+bucket are served at /api/v1/history/{host}/{owner}/{repo}/latest, and the
+scores of each run at /api/v1/history/{host}/{owner}/{repo}/scores.
+
+If --api-keys-file is set, requests other than for badges, webhook deliveries
+and the OpenAPI document must send one of its keys as a bearer token or in the
+X-API-Key header. Each key is limited to its requests-per-minute, and requests
+over it get 429 Too Many Requests with a Retry-After header.
+
+Metrics are served in the Prometheus format at /metrics. /healthz reports
+whether the server is up, and /readyz whether it can scan repos with the
+GitHub credentials it was given.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := zap.NewProductionConfig()
 		cfg.Level.SetLevel(*logLevel)
@@ -53,72 +113,143 @@ var serveCmd = &cobra.Command{
 		//nolint
 		defer logger.Sync() // flushes buffer, if any
 		sugar := logger.Sugar()
-		t, err := template.New("webpage").Parse(tpl)
+
+		httpClient := &http.Client{
+			Transport: newTransport(context.Background(), sugar),
+		}
+		opts, err := makeServerOptions(sugar, httpClient)
+		if err != nil {
+			sugar.Panic(err)
+		}
+		opts.Jobs = &jobOptions
+		// The secret is read from the environment so that it does not show up in process listings.
+		opts.WebhookSecret = os.Getenv(webhookSecretEnv)
+		if historyOptions.BucketURL != "" {
+			opts.History = &historyOptions
+		}
+		srv, err := server.New(opts)
+		if err != nil {
+			sugar.Panic(err)
+		}
+		defer srv.Close()
+		handler, err := withMonitoring(httpClient, srv)
 		if err != nil {
 			sugar.Panic(err)
 		}
-
-		http.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
-			repoParam := r.URL.Query().Get("repo")
-			const length = 3
-			s := strings.SplitN(repoParam, "/", length)
-			if len(s) != length {
-				rw.WriteHeader(http.StatusBadRequest)
-			}
-			repo := repos.RepoURL{}
-			if err := repo.Set(repoParam); err != nil {
-				rw.WriteHeader(http.StatusBadRequest)
-			}
-			sugar.Info(repoParam)
-			ctx := r.Context()
-			rt := roundtripper.NewTransport(ctx, sugar)
-			httpClient := &http.Client{
-				Transport: rt,
-			}
-			githubClient := github.NewClient(httpClient)
-			graphClient := githubv4.NewClient(httpClient)
-			repoClient := githubrepo.CreateGithubRepoClient(ctx, githubClient, graphClient)
-			repoResult, err := pkg.RunScorecards(ctx, repo, checks.AllChecks, repoClient, httpClient, githubClient, graphClient)
-			if err != nil {
-				sugar.Error(err)
-				rw.WriteHeader(http.StatusInternalServerError)
-			}
-
-			if r.Header.Get("Content-Type") == "application/json" {
-				if err := repoResult.AsJSON(showDetails, *logLevel, rw); err != nil {
-					sugar.Error(err)
-					rw.WriteHeader(http.StatusInternalServerError)
-				}
-				return
-			}
-			if err := t.Execute(rw, repoResult); err != nil {
-				sugar.Warn(err)
-			}
-		})
 		port := os.Getenv("PORT")
 		if port == "" {
 			port = "8080"
 		}
 		fmt.Printf("Listening on localhost:%s\n", port)
-		err = http.ListenAndServe(fmt.Sprintf("0.0.0.0:%s", port), nil)
+		err = http.ListenAndServe(fmt.Sprintf("0.0.0.0:%s", port), handler)
 		if err != nil {
 			log.Fatal("ListenAndServe ", err)
 		}
 	},
 }
 
-const tpl = `
-<!DOCTYPE html>
-<html>
-	<head>
-		<meta charset="UTF-8">
-		<title>Scorecard Results for: {{.Repo}}</title>
-	</head>
-	<body>
-		{{range .Checks}}
-			<div>
-				<p>{{ .Name }}: {{ .Pass }}</p>
-			</div>
-		{{end}}
-	</body>
-</html>`
+// Returns the options of the scoring engine shared by serve and grpc.
+func makeServerOptions(logger *zap.SugaredLogger, httpClient *http.Client) (server.Options, error) {
+	opts := server.Options{
+		Logger:      logger,
+		Score:       makeScoreFunc(httpClient),
+		ScoreStream: makeScoreStreamFunc(httpClient),
+		Checks:      getEnabledChecks(),
+		LogLevel:    *logLevel,
+		ScanTimeout: scanTimeout,
+	}
+	if apiKeysFile != "" {
+		var err error
+		if opts.APIKeys, err = server.LoadAPIKeys(apiKeysFile); err != nil {
+			return opts, fmt.Errorf("error during server.LoadAPIKeys: %w", err)
+		}
+	}
+	if resultCacheTTL > 0 {
+		opts.Cache = server.NewResultCache(resultCacheTTL, resultCacheDir)
+		opts.Head = makeHeadFunc(httpClient)
+	}
+	return opts, nil
+}
+
+// Returns a handler which serves the Prometheus metrics and health endpoints, and
+// passes other requests to srv.
+func withMonitoring(httpClient *http.Client, srv http.Handler) (http.Handler, error) {
+	if err := monitoring.RegisterViews(); err != nil {
+		return nil, fmt.Errorf("error during monitoring.RegisterViews: %w", err)
+	}
+	metrics, err := monitoring.MetricsHandler(nil)
+	if err != nil {
+		return nil, fmt.Errorf("error during monitoring.MetricsHandler: %w", err)
+	}
+	// The credentials of each GitHub Enterprise Server host are checked separately.
+	checks := map[string]monitoring.ReadinessCheck{}
+	for _, host := range append([]string{"github.com"}, githubrepo.EnterpriseHosts()...) {
+		githubClient, _, err := makeGitHubClients(httpClient, host)
+		if err != nil {
+			return nil, err
+		}
+		name := "github-tokens"
+		if host != "github.com" {
+			name += ":" + host
+		}
+		checks[name] = monitoring.TokenReadiness(githubClient)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	monitoring.HandleHealth(mux, checks)
+	mux.Handle("/", srv)
+	return mux, nil
+}
+
+// Returns a server.ScoreFunc which checks GitHub repos using httpClient.
+func makeScoreFunc(httpClient *http.Client) server.ScoreFunc {
+	scoreStream := makeScoreStreamFunc(httpClient)
+	return func(ctx context.Context, repo repos.RepoURL,
+		checksToRun checker.CheckNameToFnMap) (pkg.ScorecardResult, error) {
+		return scoreStream(ctx, repo, checksToRun, func(pkg.CheckEvent) {})
+	}
+}
+
+// Returns a server.ScoreStreamFunc which checks GitHub repos using httpClient.
+func makeScoreStreamFunc(httpClient *http.Client) server.ScoreStreamFunc {
+	return func(ctx context.Context, repo repos.RepoURL,
+		checksToRun checker.CheckNameToFnMap, onEvent pkg.CheckEventFunc) (pkg.ScorecardResult, error) {
+		githubClient, graphClient, err := makeGitHubClients(httpClient, repo.Host)
+		if err != nil {
+			return pkg.ScorecardResult{}, err
+		}
+		repoClient := githubrepo.CreateGithubRepoClient(ctx, githubClient, graphClient)
+		defer repoClient.Close()
+		ret := pkg.ScorecardResult{
+			Repo: repo.URL(),
+			Date: time.Now().Format("2006-01-02"),
+		}
+		err = pkg.StreamScorecards(ctx, repo, checksToRun, repoClient, httpClient, githubClient, graphClient,
+			func(event pkg.CheckEvent) {
+				if event.Type == pkg.CheckFinished {
+					ret.Checks = append(ret.Checks, event.Result)
+				}
+				onEvent(event)
+			})
+		if err != nil {
+			//nolint:wrapcheck
+			return pkg.ScorecardResult{}, err
+		}
+		return ret, nil
+	}
+}
+
+// Returns a server.HeadFunc which looks up the HEAD commit of GitHub repos using httpClient.
+func makeHeadFunc(httpClient *http.Client) server.HeadFunc {
+	return func(ctx context.Context, repo repos.RepoURL) (string, error) {
+		githubClient, _, err := makeGitHubClients(httpClient, repo.Host)
+		if err != nil {
+			return "", err
+		}
+		sha, _, err := githubClient.Repositories.GetCommitSHA1(ctx, repo.Owner, repo.Repo, "HEAD", "")
+		if err != nil {
+			return "", sce.CreateFrom(err, "Client.Repositories.GetCommitSHA1")
+		}
+		return sha, nil
+	}
+}
@@ -25,9 +25,31 @@ import (
 
 const partitionDateFormat = "20060102"
 
+// resultSchema is the schema of the results written by the worker, i.e. of
+// pkg.ScorecardResult encoded as JSON with details. New fields must be nullable or
+// repeated, so that they can be added to the table by later loads.
+var resultSchema = bigquery.Schema{
+	{Name: "Repo", Type: bigquery.StringFieldType},
+	{Name: "Date", Type: bigquery.DateFieldType},
+	{
+		Name:     "Checks",
+		Type:     bigquery.RecordFieldType,
+		Repeated: true,
+		Schema: bigquery.Schema{
+			{Name: "Name", Type: bigquery.StringFieldType},
+			{Name: "Details", Type: bigquery.StringFieldType, Repeated: true},
+			{Name: "Confidence", Type: bigquery.IntegerFieldType},
+			{Name: "Pass", Type: bigquery.BooleanFieldType},
+			{Name: "ErrorClass", Type: bigquery.StringFieldType},
+		},
+	},
+	{Name: "Metadata", Type: bigquery.StringFieldType, Repeated: true},
+}
+
 func createGCSRef(bucketURL, fileURI string) *bigquery.GCSReference {
 	gcsRef := bigquery.NewGCSReference(fmt.Sprintf("%s/%s", bucketURL, fileURI))
 	gcsRef.SourceFormat = bigquery.JSON
+	gcsRef.Schema = resultSchema
 	return gcsRef
 }
 
@@ -41,6 +63,8 @@ func createBQLoader(ctx context.Context, projectID, datasetName, tableName strin
 	partitionedTable := fmt.Sprintf("%s$%s", tableName, partitionDate.Format(partitionDateFormat))
 	loader := bqClient.Dataset(datasetName).Table(partitionedTable).LoaderFrom(gcsRef)
 	loader.WriteDisposition = bigquery.WriteTruncate
+	// Adds the fields of resultSchema which are not in the table yet.
+	loader.SchemaUpdateOptions = []string{"ALLOW_FIELD_ADDITION"}
 	return bqClient, loader, nil
 }
 
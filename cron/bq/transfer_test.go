@@ -0,0 +1,77 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/pkg"
+)
+
+// TestResultSchema checks that all fields of the results written by the worker are in
+// resultSchema, so that none are dropped by BigQuery.
+func TestResultSchema(t *testing.T) {
+	t.Parallel()
+	result := pkg.ScorecardResult{
+		Repo: "github.com/ossf/scorecard",
+		Date: "2021-08-01",
+		Checks: []checker.CheckResult{
+			{
+				Name:       "Code-Review",
+				Details:    []string{"detail"},
+				Confidence: checker.MaxResultConfidence,
+				ErrorClass: "ErrRateLimited",
+				Error2:     errors.New("rate limited"), // nolint: goerr113
+			},
+		},
+		Metadata: []string{"metadata"},
+	}
+	var buf bytes.Buffer
+	if err := result.AsJSON(true /*showDetails*/, zapcore.InfoLevel, &buf); err != nil {
+		t.Fatalf("AsJSON: %v", err)
+	}
+	var row map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &row); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	checkFields(t, "", row, resultSchema)
+}
+
+func checkFields(t *testing.T, prefix string, row map[string]interface{}, schema bigquery.Schema) {
+	t.Helper()
+	fields := make(map[string]*bigquery.FieldSchema)
+	for _, field := range schema {
+		fields[field.Name] = field
+	}
+	for name, value := range row {
+		field, ok := fields[name]
+		if !ok {
+			t.Errorf("field %s%s is not in the schema", prefix, name)
+			continue
+		}
+		if records, ok := value.([]interface{}); ok && field.Type == bigquery.RecordFieldType {
+			for _, record := range records {
+				checkFields(t, prefix+name+".", record.(map[string]interface{}), field.Schema)
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package data
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ScorecardClient is the client API for Scorecard service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScorecardClient interface {
+	// Score runs the checks on a repo and returns the result.
+	Score(ctx context.Context, in *ScoreRequest, opts ...grpc.CallOption) (*ScoreResponse, error)
+	// ScoreStream runs the checks on a repo and sends the result of each check as
+	// soon as it completes.
+	ScoreStream(ctx context.Context, in *ScoreRequest, opts ...grpc.CallOption) (Scorecard_ScoreStreamClient, error)
+}
+
+type scorecardClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScorecardClient(cc grpc.ClientConnInterface) ScorecardClient {
+	return &scorecardClient{cc}
+}
+
+func (c *scorecardClient) Score(ctx context.Context, in *ScoreRequest, opts ...grpc.CallOption) (*ScoreResponse, error) {
+	out := new(ScoreResponse)
+	err := c.cc.Invoke(ctx, "/ossf.scorecard.cron.data.Scorecard/Score", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scorecardClient) ScoreStream(ctx context.Context, in *ScoreRequest, opts ...grpc.CallOption) (Scorecard_ScoreStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Scorecard_ServiceDesc.Streams[0], "/ossf.scorecard.cron.data.Scorecard/ScoreStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scorecardScoreStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Scorecard_ScoreStreamClient interface {
+	Recv() (*CheckResult, error)
+	grpc.ClientStream
+}
+
+type scorecardScoreStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *scorecardScoreStreamClient) Recv() (*CheckResult, error) {
+	m := new(CheckResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ScorecardServer is the server API for Scorecard service.
+// All implementations must embed UnimplementedScorecardServer
+// for forward compatibility
+type ScorecardServer interface {
+	// Score runs the checks on a repo and returns the result.
+	Score(context.Context, *ScoreRequest) (*ScoreResponse, error)
+	// ScoreStream runs the checks on a repo and sends the result of each check as
+	// soon as it completes.
+	ScoreStream(*ScoreRequest, Scorecard_ScoreStreamServer) error
+	mustEmbedUnimplementedScorecardServer()
+}
+
+// UnimplementedScorecardServer must be embedded to have forward compatible implementations.
+type UnimplementedScorecardServer struct {
+}
+
+func (UnimplementedScorecardServer) Score(context.Context, *ScoreRequest) (*ScoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Score not implemented")
+}
+func (UnimplementedScorecardServer) ScoreStream(*ScoreRequest, Scorecard_ScoreStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ScoreStream not implemented")
+}
+func (UnimplementedScorecardServer) mustEmbedUnimplementedScorecardServer() {}
+
+// UnsafeScorecardServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScorecardServer will
+// result in compilation errors.
+type UnsafeScorecardServer interface {
+	mustEmbedUnimplementedScorecardServer()
+}
+
+func RegisterScorecardServer(s grpc.ServiceRegistrar, srv ScorecardServer) {
+	s.RegisterService(&Scorecard_ServiceDesc, srv)
+}
+
+func _Scorecard_Score_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScorecardServer).Score(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ossf.scorecard.cron.data.Scorecard/Score",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScorecardServer).Score(ctx, req.(*ScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scorecard_ScoreStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ScoreRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScorecardServer).ScoreStream(m, &scorecardScoreStreamServer{stream})
+}
+
+type Scorecard_ScoreStreamServer interface {
+	Send(*CheckResult) error
+	grpc.ServerStream
+}
+
+type scorecardScoreStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *scorecardScoreStreamServer) Send(m *CheckResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Scorecard_ServiceDesc is the grpc.ServiceDesc for Scorecard service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Scorecard_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ossf.scorecard.cron.data.Scorecard",
+	HandlerType: (*ScorecardServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Score",
+			Handler:    _Scorecard_Score_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ScoreStream",
+			Handler:       _Scorecard_ScoreStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "cron/data/scorecard.proto",
+}
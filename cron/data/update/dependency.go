@@ -21,17 +21,21 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 
-	"github.com/go-git/go-git/v5"
 	"github.com/google/go-github/v33/github"
-	"golang.org/x/tools/go/vcs"
+	"go.uber.org/zap"
 
 	"github.com/ossf/scorecard/v2/cron/data"
+	"github.com/ossf/scorecard/v2/manifests"
 	"github.com/ossf/scorecard/v2/repos"
+	"github.com/ossf/scorecard/v2/resolvers"
+	"github.com/ossf/scorecard/v2/roundtripper"
 )
 
 var (
@@ -73,23 +77,16 @@ var (
 
 type repositoryDepsURL struct {
 	Owner, Repo, File string
-	Vendor            bool
+	// Vendor is set for repos which vendor their dependencies, so that their
+	// vendor/modules.txt lists them.
+	Vendor bool
 }
 
 // Programmatically gets Envoy's dependencies and add to projects.
 // Re-using a checker type.
-func getBazelDeps(repo repositoryDepsURL) []repos.RepoURL {
-	client := github.NewClient(nil)
-	ctx := context.Background()
+func getBazelDeps(ctx context.Context, client *github.Client, repo repositoryDepsURL) []repos.RepoURL {
 	depRepos := []repos.RepoURL{}
-	fo, _, _, err := client.Repositories.GetContents(ctx, repo.Owner, repo.Repo, repo.File, nil)
-	if err != nil {
-		// If we can't get content, gracefully fail but alert.
-		log.Panicf("Failed to get repository content %s", err)
-		return depRepos
-	}
-
-	fc, err := fo.GetContent()
+	fc, err := getContent(ctx, client, repo, repo.File)
 	if err != nil {
 		// If we can't get content, gracefully fail, but alert.
 		log.Panicf("Failed to get repository content %s", err)
@@ -111,91 +108,107 @@ func getBazelDeps(repo repositoryDepsURL) []repos.RepoURL {
 	return depRepos
 }
 
-// GetGoDeps returns go repo dependencies.
-func getGoDeps(repo repositoryDepsURL) []repos.RepoURL {
-	repoURLs := []repos.RepoURL{}
-	pwd, err := os.Getwd()
+// getContent returns the content of the file at path in repo.
+func getContent(ctx context.Context, client *github.Client, repo repositoryDepsURL, path string) (string, error) {
+	fo, _, _, err := client.Repositories.GetContents(ctx, repo.Owner, repo.Repo, path, nil)
 	if err != nil {
-		log.Default().Println(err)
-		return nil
+		return "", fmt.Errorf("error during Repositories.GetContents: %w", err)
 	}
-	//nolint
-	defer os.Chdir(pwd)
-	// creating temp dir for git clone
-	gitDir, err := ioutil.TempDir(pwd, "")
+	content, err := fo.GetContent()
 	if err != nil {
-		log.Default().Println("Cannot create temporary dir", err)
-		return nil
-	}
-	defer os.RemoveAll(gitDir)
-
-	// cloning git repo to get `go list -m all` out for getting all the dependencies
-	_, err = git.PlainClone(gitDir, false,
-		&git.CloneOptions{URL: fmt.Sprintf("http://github.com/%s/%s", repo.Owner, repo.Repo)})
-	if err != nil {
-		log.Default().Println(err)
-		return nil
-	}
-
-	if err := os.Chdir(gitDir); err != nil {
-		log.Default().Println(err)
-		return nil
+		return "", fmt.Errorf("error during GetContent: %w", err)
 	}
+	return content, nil
+}
 
-	var cmd *exec.Cmd
+// getGoModules returns the paths of all the modules in the build list of repo,
+// including indirect dependencies.
+func getGoModules(ctx context.Context, client *github.Client, repo repositoryDepsURL) ([]string, error) {
+	var deps []manifests.Dependency
 	if repo.Vendor {
-		cmd = exec.Command("go", "list", "-e", "mod=vendor", "all")
+		// vendor/modules.txt lists all the modules of the build.
+		content, err := getContent(ctx, client, repo, "vendor/"+manifests.ModulesTxt)
+		if err != nil {
+			return nil, err
+		}
+		if deps, err = manifests.Parse(manifests.ModulesTxt, []byte(content)); err != nil {
+			return nil, fmt.Errorf("error during manifests.Parse: %w", err)
+		}
 	} else {
-		cmd = exec.Command("go", "list", "-m", "all")
+		goMod, err := getContent(ctx, client, repo, manifests.GoMod)
+		if err != nil {
+			return nil, err
+		}
+		goSum, err := getContent(ctx, client, repo, "go.sum")
+		if err != nil {
+			return nil, err
+		}
+		return listGoModules(goMod, goSum)
 	}
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err = cmd.Run()
-	if err != nil {
-		log.Default().Println(err)
-		return nil
+	ret := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		ret = append(ret, dep.Name)
 	}
+	return ret, nil
+}
 
-	/*
-		   example output of go list -m all
-			gopkg.in/resty.v1 v1.12.0
-			gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7
-	*/
-	for _, l := range strings.Split(out.String(), "\n") {
-		dependency := strings.Split(l, " ")[0]
-		if strings.HasPrefix(dependency, "github.com") {
-			repoURLs = parseGoModURL(dependency, repoURLs)
-		} else {
-			dependency = getVanityRepoURL(dependency)
-			repoURLs = parseGoModURL(dependency, repoURLs)
-		}
+// listGoModules returns the modules which `go list -m all` lists for a module
+// with the given go.mod and go.sum, except the module itself.
+func listGoModules(goMod, goSum string) ([]string, error) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		return nil, fmt.Errorf("error during ioutil.TempDir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	// nolint: gomnd
+	if err := ioutil.WriteFile(filepath.Join(dir, manifests.GoMod), []byte(goMod), 0o600); err != nil {
+		return nil, fmt.Errorf("error writing go.mod: %w", err)
+	}
+	// nolint: gomnd
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.sum"), []byte(goSum), 0o600); err != nil {
+		return nil, fmt.Errorf("error writing go.sum: %w", err)
 	}
-	return repoURLs
-}
 
-// getVanityRepoURL returns actual git repository for the go vanity URL
-// https://github.com/GoogleCloudPlatform/govanityurls.
-func getVanityRepoURL(u string) string {
-	repo, err := vcs.RepoRootForImportDynamic(u, false)
+	cmd := exec.Command("go", "list", "-mod=mod", "-m", "-f", "{{if not .Main}}{{.Path}}{{end}}", "all")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
 	if err != nil {
-		log.Default().Println("unable to parse the vanity URL", u, err)
-		return ""
+		return nil, fmt.Errorf("error during go list: %w: %s", err, stderr.String())
 	}
-	return repo.Repo
+	return strings.Fields(string(out)), nil
 }
 
-func parseGoModURL(dependency string, repoURLs []repos.RepoURL) []repos.RepoURL {
-	repoURL := repos.RepoURL{}
-	splitURL := strings.Split(dependency, "/")
-	// nolint:gomnd
-	if len(splitURL) < 3 {
-		return repoURLs
+// getGoDeps returns the repos of all the modules repo depends on, including
+// indirect dependencies.
+func getGoDeps(ctx context.Context, client *github.Client, repo repositoryDepsURL) []repos.RepoURL {
+	modules, err := getGoModules(ctx, client, repo)
+	if err != nil {
+		log.Default().Println(err)
+		return nil
 	}
-	u := fmt.Sprintf("%s/%s/%s", splitURL[0], splitURL[1], splitURL[2])
-	if err := repoURL.Set(u); err != nil {
-		return repoURLs
+	resolver, err := resolvers.Get(resolvers.GoModules, http.DefaultClient)
+	if err != nil {
+		log.Default().Println(err)
+		return nil
+	}
+
+	repoURLs := []repos.RepoURL{}
+	for _, module := range modules {
+		// The resolver also follows vanity import paths, e.g. k8s.io/api, to their repo.
+		git, err := resolver.Resolve(ctx, module)
+		if err != nil {
+			log.Default().Println("unable to resolve", module, err)
+			continue
+		}
+		repoURL := repos.RepoURL{}
+		if err := repoURL.Set(git); err != nil {
+			log.Default().Println(err)
+			continue
+		}
+		repoURLs = append(repoURLs, repoURL)
 	}
-	repoURLs = append(repoURLs, repoURL)
 	return repoURLs
 }
 
@@ -218,10 +231,19 @@ func getDependencies(in io.Reader) (oldRepos, newRepos []repos.RepoURL, e error)
 		m[repo.URL()] = repo.Metadata
 	}
 
+	// Fetch from GitHub with the same credentials as the cron workers, so that the
+	// requests are not limited to the unauthenticated rate limit.
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error during zap.NewProduction: %w", err)
+	}
+	ctx := context.Background()
+	client := github.NewClient(&http.Client{Transport: roundtripper.NewTransport(ctx, logger.Sugar())})
+
 	// Create a list of project dependencies that are not already present.
 	newRepos = []repos.RepoURL{}
 	for _, repo := range bazelRepos {
-		for _, item := range getBazelDeps(repo) {
+		for _, item := range getBazelDeps(ctx, client, repo) {
 			if _, ok := m[item.URL()]; !ok {
 				// Also add to m to avoid dupes.
 				m[item.URL()] = item.Metadata
@@ -230,7 +252,7 @@ func getDependencies(in io.Reader) (oldRepos, newRepos []repos.RepoURL, e error)
 		}
 	}
 	for _, repo := range gorepos {
-		for _, item := range getGoDeps(repo) {
+		for _, item := range getGoDeps(ctx, client, repo) {
 			if _, ok := m[item.URL()]; !ok {
 				// Also add to m to avoid dupes.
 				m[item.URL()] = item.Metadata
@@ -37,6 +37,7 @@ const (
 	stackdriverTimeoutMinutes               = 10
 	stackDriver                exporterType = "stackdriver"
 	printer                    exporterType = "printer"
+	prometheusExporterType     exporterType = "prometheus"
 )
 
 // Exporter interface is a custom wrapper to represent an opencensus exporter.
@@ -47,7 +48,8 @@ type Exporter interface {
 	Flush()
 }
 
-// GetExporter defines a factory for returning opencensus Exporter.
+// GetExporter defines a factory for returning opencensus Exporter. The prometheus
+// exporter serves metrics over HTTP, see MetricsHandler.
 func GetExporter() (Exporter, error) {
 	exporter, err := config.GetMetricExporter()
 	if err != nil {
@@ -58,6 +60,8 @@ func GetExporter() (Exporter, error) {
 		return newStackDriverExporter()
 	case printer:
 		return new(printerExporter), nil
+	case prometheusExporterType:
+		return newPrometheusExporter()
 	default:
 		return nil, fmt.Errorf("%w: %s", errorUndefinedExporter, exporter)
 	}
@@ -0,0 +1,132 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+const (
+	readinessTimeout = 10 * time.Second
+	// tokenCheckInterval limits how often the GitHub rate limit is looked up.
+	tokenCheckInterval = 30 * time.Second
+)
+
+var (
+	errRateLimitExhausted = errors.New("rate limit exhausted")
+	errNoAttempt          = errors.New("not attempted yet")
+)
+
+// ReadinessCheck returns an error if a dependency is not ready.
+type ReadinessCheck func(ctx context.Context) error
+
+// HandleHealth registers `/healthz`, which succeeds as long as the process serves
+// requests, and `/readyz`, which fails with 503 Service Unavailable unless all the
+// checks pass, on mux. Both list the outcome of each check in plain text.
+func HandleHealth(mux *http.ServeMux, checks map[string]ReadinessCheck) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		defer cancel()
+		names := make([]string, 0, len(checks))
+		for name := range checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var body strings.Builder
+		ready := true
+		for _, name := range names {
+			if err := checks[name](ctx); err != nil {
+				ready = false
+				fmt.Fprintf(&body, "[-]%s failed: %v\n", name, err)
+				continue
+			}
+			fmt.Fprintf(&body, "[+]%s ok\n", name)
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		fmt.Fprint(w, body.String())
+	})
+}
+
+// TokenReadiness returns a ReadinessCheck which fails if the GitHub credentials used
+// by client are rejected, or have exhausted their core rate limit. The rate limit is
+// looked up at most every 30 seconds, which does not count against it.
+func TokenReadiness(client *github.Client) ReadinessCheck {
+	var mu sync.Mutex
+	var lastErr error
+	var checked time.Time
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if time.Since(checked) < tokenCheckInterval {
+			return lastErr
+		}
+		limits, _, err := client.RateLimits(ctx)
+		switch {
+		case err != nil:
+			lastErr = sce.CreateFrom(err, "Client.RateLimits")
+		case limits.GetCore().Remaining == 0 && time.Now().Before(limits.GetCore().Reset.Time):
+			lastErr = fmt.Errorf("%w until %s", errRateLimitExhausted, limits.GetCore().Reset.Format(time.RFC3339))
+		default:
+			lastErr = nil
+		}
+		checked = time.Now()
+		return lastErr
+	}
+}
+
+// Status is a ReadinessCheck which reports the outcome of the latest attempt of an
+// operation, e.g. pulling from a subscription. It fails until an attempt is recorded.
+type Status struct {
+	mu        sync.Mutex
+	err       error
+	attempted bool
+}
+
+// Set records the outcome of an attempt.
+func (s *Status) Set(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	s.attempted = true
+}
+
+// Check returns the error of the latest attempt.
+func (s *Status) Check(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.attempted {
+		return errNoAttempt
+	}
+	return s.err
+}
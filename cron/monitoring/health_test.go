@@ -0,0 +1,167 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+)
+
+var errTest = errors.New("test error")
+
+func TestHandleHealth(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		path     string
+		checks   map[string]ReadinessCheck
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "healthz ignores checks",
+			path: "/healthz",
+			checks: map[string]ReadinessCheck{
+				"failing": func(context.Context) error { return errTest },
+			},
+			wantCode: http.StatusOK,
+			wantBody: "ok\n",
+		},
+		{
+			name: "ready",
+			path: "/readyz",
+			checks: map[string]ReadinessCheck{
+				"b": func(context.Context) error { return nil },
+				"a": func(context.Context) error { return nil },
+			},
+			wantCode: http.StatusOK,
+			wantBody: "[+]a ok\n[+]b ok\n",
+		},
+		{
+			name: "not ready",
+			path: "/readyz",
+			checks: map[string]ReadinessCheck{
+				"a": func(context.Context) error { return nil },
+				"b": func(context.Context) error { return errTest },
+			},
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: "[+]a ok\n[-]b failed: test error\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Re-initializing variable so it is not changed while executing the closure below
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mux := http.NewServeMux()
+			HandleHealth(mux, tt.checks)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestTokenReadiness(t *testing.T) {
+	t.Parallel()
+	reset := time.Now().Add(time.Hour).Unix()
+	tests := []struct {
+		name      string
+		remaining int
+		status    int
+		wantErr   bool
+	}{
+		{
+			name:      "remaining",
+			remaining: 10,
+			status:    http.StatusOK,
+		},
+		{
+			name:      "exhausted",
+			remaining: 0,
+			status:    http.StatusOK,
+			wantErr:   true,
+		},
+		{
+			name:    "bad credentials",
+			status:  http.StatusUnauthorized,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Re-initializing variable so it is not changed while executing the closure below
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			requests := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				if tt.status != http.StatusOK {
+					fmt.Fprint(w, `{"message":"Bad credentials"}`)
+					return
+				}
+				fmt.Fprintf(w, `{"resources":{"core":{"limit":5000,"remaining":%d,"reset":%d}}}`,
+					tt.remaining, reset)
+			}))
+			t.Cleanup(ts.Close)
+			client := github.NewClient(ts.Client())
+			baseURL, err := url.Parse(ts.URL + "/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.BaseURL = baseURL
+
+			check := TokenReadiness(client)
+			for i := 0; i < 2; i++ {
+				if err := check(context.Background()); (err != nil) != tt.wantErr {
+					t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+			if requests != 1 {
+				t.Errorf("rate limit looked up %d times, want 1", requests)
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+	var status Status
+	ctx := context.Background()
+	if err := status.Check(ctx); !errors.Is(err, errNoAttempt) {
+		t.Errorf("Check before any attempt = %v, want %v", err, errNoAttempt)
+	}
+	status.Set(errTest)
+	if err := status.Check(ctx); !errors.Is(err, errTest) {
+		t.Errorf("Check after a failed attempt = %v, want %v", err, errTest)
+	}
+	status.Set(nil)
+	if err := status.Check(ctx); err != nil {
+		t.Errorf("Check after a successful attempt = %v, want nil", err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"fmt"
+	"net/http"
+
+	"contrib.go.opencensus.io/exporter/prometheus"
+	"go.opencensus.io/stats/view"
+
+	"github.com/ossf/scorecard/v2/clients/githubrepo"
+	"github.com/ossf/scorecard/v2/stats"
+)
+
+const prometheusNamespace = "scorecard"
+
+// prometheusExporter serves the registered views to Prometheus, which scrapes them,
+// so there is nothing to start, stop or flush.
+type prometheusExporter struct {
+	*prometheus.Exporter
+}
+
+func (pe *prometheusExporter) StartMetricsExporter() error {
+	return nil
+}
+
+func (pe *prometheusExporter) StopMetricsExporter() {}
+
+func (pe *prometheusExporter) Flush() {}
+
+func newPrometheusExporter() (*prometheusExporter, error) {
+	exporter, err := prometheus.NewExporter(prometheus.Options{
+		Namespace: prometheusNamespace,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error during prometheus.NewExporter: %w", err)
+	}
+	return &prometheusExporter{exporter}, nil
+}
+
+// MetricsHandler returns a handler serving the registered views in the Prometheus
+// format. If exporter is a Prometheus exporter, its handler is returned. Otherwise,
+// a Prometheus exporter is created alongside it.
+func MetricsHandler(exporter Exporter) (http.Handler, error) {
+	if handler, ok := exporter.(http.Handler); ok {
+		return handler, nil
+	}
+	return newPrometheusExporter()
+}
+
+// RegisterViews registers the views of the measures recorded while running checks
+// and serving the API.
+func RegisterViews() error {
+	if err := view.Register(
+		&stats.CheckRuntime,
+		&stats.CheckErrorCount,
+		&stats.RepoRuntime,
+		&stats.OutgoingHTTPRequests,
+		&stats.HTTPRetryWaitTime,
+		&githubrepo.GithubTokens,
+		&stats.APIKeyRequests); err != nil {
+		return fmt.Errorf("error during view.Register: %w", err)
+	}
+	return nil
+}
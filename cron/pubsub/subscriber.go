@@ -35,6 +35,13 @@ type Subscriber interface {
 	Close() error
 }
 
+// PullObserver is implemented by subscribers which report the outcome of each pull from
+// the subscription, including the pulls which return no message while SynchronousPull
+// waits for one.
+type PullObserver interface {
+	ObservePulls(observe func(err error))
+}
+
 // CreateSubscriber returns an implementation of Subscriber interface.
 // Currently returns an instance of gcsSubscriber.
 func CreateSubscriber(ctx context.Context, subscriptionURL string) (Subscriber, error) {
@@ -40,6 +40,7 @@ type gcsSubscriber struct {
 	client          *pubsub.SubscriberClient
 	subscriptionURL string
 	recvdAckID      string
+	observePull     func(err error)
 }
 
 func createGCSSubscriber(ctx context.Context, subscriptionURL string) (*gcsSubscriber, error) {
@@ -89,9 +90,11 @@ func (subscriber *gcsSubscriber) SynchronousPull() (*data.ScorecardBatchRequest,
 			Subscription: subscriber.subscriptionURL,
 			MaxMessages:  maxMessagesToPull,
 		})
+		if subscriber.observePull != nil {
+			subscriber.observePull(err)
+		}
 		if err != nil {
-			log.Printf("error during Recieive: %v", err)
-			return nil, nil
+			return nil, fmt.Errorf("error during Pull: %w", err)
 		}
 		numReceivedMessages = len(result.ReceivedMessages)
 		if numReceivedMessages > 0 {
@@ -114,6 +117,11 @@ func (subscriber *gcsSubscriber) SynchronousPull() (*data.ScorecardBatchRequest,
 	return parseJSONToRequest(msgToProcess.GetMessage().GetData())
 }
 
+// ObservePulls implements PullObserver.
+func (subscriber *gcsSubscriber) ObservePulls(observe func(err error)) {
+	subscriber.observePull = observe
+}
+
 func (subscriber *gcsSubscriber) Ack() {
 	err := subscriber.client.Acknowledge(subscriber.ctx, &pubsubpb.AcknowledgeRequest{
 		Subscription: subscriber.subscriptionURL,
@@ -23,13 +23,13 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	// nolint:gosec
 	_ "net/http/pprof"
 
 	"github.com/google/go-github/v32/github"
 	"github.com/shurcooL/githubv4"
-	"go.opencensus.io/stats/view"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
@@ -45,12 +45,19 @@ import (
 	"github.com/ossf/scorecard/v2/pkg"
 	"github.com/ossf/scorecard/v2/repos"
 	"github.com/ossf/scorecard/v2/roundtripper"
-	"github.com/ossf/scorecard/v2/stats"
 )
 
 var (
 	ignoreRuntimeErrors = flag.Bool("ignoreRuntimeErrors", false, "if set to true any runtime errors will be ignored")
 	errIgnore           *clients.ErrRepoUnavailable
+
+	errSubscriptionClosed = errors.New("subscription returned nil message")
+)
+
+const (
+	// minPullBackoff is the wait before retrying a failed pull, doubled on each failure.
+	minPullBackoff = time.Second
+	maxPullBackoff = time.Minute
 )
 
 func processRequest(ctx context.Context,
@@ -98,7 +105,10 @@ func processRequest(ctx context.Context,
 		if !(*ignoreRuntimeErrors) {
 			for checkIndex := range result.Checks {
 				check := &result.Checks[checkIndex]
-				if errors.As(check.Error2, &sce.ErrScorecardInternal) {
+				// Transient errors fail the request so that it is retried.
+				if errors.Is(check.Error2, sce.ErrScorecardInternal) ||
+					errors.Is(check.Error2, sce.ErrRateLimited) ||
+					errors.Is(check.Error2, sce.ErrTimeout) {
 					// nolint: errorlint, goerr113
 					return fmt.Errorf("check %s has a runtime error: %v", check.Name, check.Error2)
 				}
@@ -148,17 +158,28 @@ func startMetricsExporter() (monitoring.Exporter, error) {
 		return nil, fmt.Errorf("error in StartMetricsExporter: %w", err)
 	}
 
-	if err := view.Register(
-		&stats.CheckRuntime,
-		&stats.CheckErrorCount,
-		&stats.RepoRuntime,
-		&stats.OutgoingHTTPRequests,
-		&githubrepo.GithubTokens); err != nil {
-		return nil, fmt.Errorf("error during view.Register: %w", err)
+	if err := monitoring.RegisterViews(); err != nil {
+		return nil, fmt.Errorf("error during monitoring.RegisterViews: %w", err)
 	}
 	return exporter, nil
 }
 
+// handleMonitoring registers the Prometheus metrics and health endpoints, served
+// alongside the runtime profiles.
+func handleMonitoring(exporter monitoring.Exporter, githubClient *github.Client,
+	subscriberStatus *monitoring.Status) error {
+	metrics, err := monitoring.MetricsHandler(exporter)
+	if err != nil {
+		return fmt.Errorf("error during monitoring.MetricsHandler: %w", err)
+	}
+	http.Handle("/metrics", metrics)
+	monitoring.HandleHealth(http.DefaultServeMux, map[string]monitoring.ReadinessCheck{
+		"github-tokens": monitoring.TokenReadiness(githubClient),
+		"subscriber":    subscriberStatus.Check,
+	})
+	return nil
+}
+
 func main() {
 	ctx := context.Background()
 
@@ -185,7 +206,15 @@ func main() {
 	}
 	defer exporter.StopMetricsExporter()
 
-	// Exposed for monitoring runtime profiles
+	// The worker is unready until the subscription was pulled, and while pulls fail.
+	var subscriberStatus monitoring.Status
+	if observer, ok := subscriber.(pubsub.PullObserver); ok {
+		observer.ObservePulls(subscriberStatus.Set)
+	}
+	if err := handleMonitoring(exporter, githubClient, &subscriberStatus); err != nil {
+		panic(err)
+	}
+	// Exposed for monitoring runtime profiles, metrics and health.
 	go func() {
 		log.Fatal(http.ListenAndServe(":8080", nil))
 	}()
@@ -197,13 +226,29 @@ func main() {
 	// All of the checks from cron would fail and uses another call to the API.
 	// This will reduce usage of the API.
 	delete(checksToRun, checks.CheckBranchProtection)
+	backoff := minPullBackoff
 	for {
 		req, err := subscriber.SynchronousPull()
+		if err != nil && !errors.Is(err, pubsub.ErrorInParse) {
+			subscriberStatus.Set(err)
+			log.Printf("error pulling from subscription, retrying in %s: %v", backoff, err)
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > maxPullBackoff {
+				backoff = maxPullBackoff
+			}
+			continue
+		}
+		subscriberStatus.Set(nil)
+		backoff = minPullBackoff
 		if err != nil {
-			panic(err)
+			log.Printf("error parsing message: %v", err)
+			// Nack the message so that it stops being extended, and is redelivered.
+			subscriber.Nack()
+			continue
 		}
 		log.Print("Received message from subscription")
 		if req == nil {
+			subscriberStatus.Set(errSubscriptionClosed)
 			log.Print("subscription returned nil message during Receive, exiting")
 			break
 		}
@@ -27,11 +27,27 @@ import (
 //go:embed checks.yaml
 var checksYAML []byte
 
+// Requirements a check can list under `requires` in checks.yaml.
+const (
+	// RequiresAdminToken means the check uses APIs restricted to repository admins.
+	RequiresAdminToken = "admin-token"
+	// RequiresSearchAPI means the check uses the GitHub code search API.
+	RequiresSearchAPI = "search-api"
+	// RequiresGraphQL means the check uses the GitHub GraphQL API.
+	RequiresGraphQL = "graphql"
+)
+
 // Check defines expected check definition in checks.yaml.
 type Check struct {
-	Risk        string   `yaml:"-"`
+	Risk        string   `yaml:"risk"`
 	Description string   `yaml:"description"`
 	Remediation []string `yaml:"remediation"`
+	Requires    []string `yaml:"requires"`
+}
+
+// RequiresAuth returns true if the check cannot run with unauthenticated requests.
+func (c *Check) RequiresAuth() bool {
+	return len(c.Requires) > 0
 }
 
 // Doc maps to checks.yaml file.
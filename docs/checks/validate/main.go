@@ -34,6 +34,18 @@ func main() {
 			// nolint: goerr113
 			panic(fmt.Errorf("could not find checkName: %s in checks.yaml", check))
 		}
+		if doc.Risk == "" {
+			// nolint: goerr113
+			panic(fmt.Errorf("risk for checkName: %s is empty", check))
+		}
+		for _, req := range doc.Requires {
+			switch req {
+			case docs.RequiresAdminToken, docs.RequiresSearchAPI, docs.RequiresGraphQL:
+			default:
+				// nolint: goerr113
+				panic(fmt.Errorf("unknown requirement for checkName: %s: %s", check, req))
+			}
+		}
 		if doc.Description == "" {
 			// nolint: goerr113
 			panic(fmt.Errorf("description for checkName: %s is empty", check))
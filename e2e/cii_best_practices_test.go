@@ -34,6 +34,7 @@ var _ = Describe("E2E TEST:CIIBestPractices", func() {
 				Client:      ghClient,
 				HTTPClient:  httpClient,
 				RepoClient:  nil,
+				Host:        "github.com",
 				Owner:       "tensorflow",
 				Repo:        "tensorflow",
 				GraphClient: graphClient,
@@ -0,0 +1,116 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+)
+
+// Matches the status code in errors for non-200 GraphQL responses, which githubv4
+// reports as plain strings.
+var graphQLStatus = regexp.MustCompile(`non-200 OK status code: (\d{3})`)
+
+// Classify returns the public error which err is an instance of. Errors returned by the
+// GitHub REST and GraphQL APIs, and by network requests, are mapped to ErrPermissionDenied,
+// ErrNotFound, ErrRateLimited and ErrTimeout. Classify returns nil for other errors.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, c := range classes {
+		if errors.Is(err, c.err) {
+			return c.err
+		}
+	}
+
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) {
+		return ErrRateLimited
+	}
+	var responseErr *github.ErrorResponse
+	if errors.As(err, &responseErr) && responseErr.Response != nil {
+		return FromHTTPStatus(responseErr.Response.StatusCode)
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrTimeout
+	}
+	return classifyGraphQL(err.Error())
+}
+
+// classifyGraphQL maps the messages of GraphQL errors to public errors.
+func classifyGraphQL(msg string) error {
+	if m := graphQLStatus.FindStringSubmatch(msg); m != nil {
+		status, err := strconv.Atoi(m[1])
+		if err == nil {
+			return FromHTTPStatus(status)
+		}
+	}
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "could not resolve to"):
+		return ErrNotFound
+	case strings.Contains(lower, "rate limit"):
+		return ErrRateLimited
+	case strings.Contains(lower, "resource not accessible"),
+		strings.Contains(lower, "must have push access"):
+		return ErrPermissionDenied
+	default:
+		return nil
+	}
+}
+
+// FromHTTPStatus returns the public error for a failed HTTP response status code, or nil
+// if there is none.
+func FromHTTPStatus(status int) error {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrPermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrTimeout
+	default:
+		return nil
+	}
+}
+
+// CreateFrom creates a public error wrapping the class of err, see Classify, with msg and
+// the message of err. Errors without a class are ErrScorecardInternal.
+// This is how errors returned by API calls should be surfaced:
+//
+//	prs, _, err := c.Client.PullRequests.List(...)
+//	if err != nil {
+//		return sce.CreateFrom(err, "Client.PullRequests.List")
+//	}
+func CreateFrom(err error, msg string) error {
+	class := Classify(err)
+	if class == nil {
+		class = ErrScorecardInternal
+	}
+	return Create(class, fmt.Sprintf("%s: %v", msg, err))
+}
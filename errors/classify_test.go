@@ -0,0 +1,114 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+)
+
+func TestGetName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		err  error
+		name string
+		want string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: "ErrUnknown",
+		},
+		{
+			name: "unknown",
+			err:  errors.New("some error"),
+			want: "ErrUnknown",
+		},
+		{
+			name: "created",
+			err:  Create(ErrInvalidRepoContent, "invalid yaml file"),
+			want: "ErrInvalidRepoContent",
+		},
+		{
+			name: "REST 403",
+			err: &github.ErrorResponse{
+				Response: &http.Response{StatusCode: http.StatusForbidden},
+				Message:  "Resource not accessible by integration",
+			},
+			want: "ErrPermissionDenied",
+		},
+		{
+			name: "REST 404",
+			err:  fmt.Errorf("wrapped: %w", &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}),
+			want: "ErrNotFound",
+		},
+		{
+			name: "REST rate limit",
+			err:  &github.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}},
+			want: "ErrRateLimited",
+		},
+		{
+			name: "REST secondary rate limit",
+			err:  &github.AbuseRateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}},
+			want: "ErrRateLimited",
+		},
+		{
+			name: "GraphQL not found",
+			err:  errors.New("Could not resolve to a Repository with the name 'owner/repo'."),
+			want: "ErrNotFound",
+		},
+		{
+			name: "GraphQL 401",
+			err:  errors.New(`non-200 OK status code: 401 Unauthorized body: "{}"`),
+			want: "ErrPermissionDenied",
+		},
+		{
+			name: "GraphQL rate limit",
+			err:  errors.New("API rate limit exceeded for user ID 1."),
+			want: "ErrRateLimited",
+		},
+		{
+			name: "timeout",
+			err:  &url.Error{Op: "Get", URL: "https://api.github.com", Err: context.DeadlineExceeded},
+			want: "ErrTimeout",
+		},
+		{
+			name: "created from API error",
+			err: CreateFrom(&github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}},
+				"Client.Repositories.Get"),
+			want: "ErrNotFound",
+		},
+		{
+			name: "created from unknown error",
+			err:  CreateFrom(errors.New("some error"), "Client.Repositories.Get"),
+			want: "ErrScorecardInternal",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := GetName(tt.err); got != tt.want {
+				t.Errorf("GetName(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
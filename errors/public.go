@@ -23,8 +23,36 @@ import (
 var (
 	ErrScorecardInternal = errors.New("internal error")
 	ErrRepoUnreachable   = errors.New("repo unreachable")
+
+	// ErrPermissionDenied indicates the credentials used lack access, e.g. a token scope.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrNotFound indicates a requested resource does not exist, or is not visible.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited indicates an API rate limit was exceeded.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrTimeout indicates a request did not complete in time.
+	ErrTimeout = errors.New("timeout")
+	// ErrInvalidRepoContent indicates a file in the repo could not be parsed.
+	ErrInvalidRepoContent = errors.New("invalid repo content")
+	// ErrUnsupportedHost indicates the repo's host is not supported.
+	ErrUnsupportedHost = errors.New("unsupported host")
 )
 
+// classes are the public errors, in the order errors are matched against them.
+var classes = []struct {
+	err  error
+	name string
+}{
+	{ErrRepoUnreachable, "ErrRepoUnreachable"},
+	{ErrPermissionDenied, "ErrPermissionDenied"},
+	{ErrNotFound, "ErrNotFound"},
+	{ErrRateLimited, "ErrRateLimited"},
+	{ErrTimeout, "ErrTimeout"},
+	{ErrInvalidRepoContent, "ErrInvalidRepoContent"},
+	{ErrUnsupportedHost, "ErrUnsupportedHost"},
+	{ErrScorecardInternal, "ErrScorecardInternal"},
+}
+
 // Create a public error using any of the errors
 // listed above. For examples, see errors/errors.md.
 func Create(e error, msg string) error {
@@ -36,14 +64,13 @@ func Create(e error, msg string) error {
 	return fmt.Errorf("%w", e)
 }
 
-// GetName returns the name of the error.
+// GetName returns the name of the class of the error, see Classify.
 func GetName(err error) string {
-	switch {
-	case errors.Is(err, ErrScorecardInternal):
-		return "ErrScorecardInternal"
-	case errors.Is(err, ErrRepoUnreachable):
-		return "ErrRepoUnreachable"
-	default:
-		return "ErrUnknown"
+	class := Classify(err)
+	for _, c := range classes {
+		if c.err == class {
+			return c.name
+		}
 	}
+	return "ErrUnknown"
 }
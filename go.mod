@@ -5,9 +5,10 @@ go 1.16
 require (
 	cloud.google.com/go/bigquery v1.19.0
 	cloud.google.com/go/pubsub v1.13.0
+	contrib.go.opencensus.io/exporter/prometheus v0.3.0
 	contrib.go.opencensus.io/exporter/stackdriver v0.13.8
+	github.com/BurntSushi/toml v0.3.1
 	github.com/bradleyfalzon/ghinstallation v1.1.1
-	github.com/go-git/go-git/v5 v5.4.2
 	github.com/golangci/golangci-lint v1.41.1
 	github.com/google/addlicense v0.0.0-20210428195630-6d92264d7170
 	github.com/google/go-cmp v0.5.6
@@ -26,9 +27,15 @@ require (
 	go.opencensus.io v0.23.0
 	go.uber.org/zap v1.18.1
 	gocloud.dev v0.23.0
-	golang.org/x/tools v0.1.5
+	golang.org/x/mod v0.4.2
+	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
+	golang.org/x/time v0.0.0-20210611083556-38a9dc6acbc6
+	golang.org/x/tools v0.1.5 // indirect
 	google.golang.org/genproto v0.0.0-20210714021259-044028024a4f
+	google.golang.org/grpc v1.39.0
+	google.golang.org/grpc/cmd/protoc-gen-go-grpc v1.1.0
 	google.golang.org/protobuf v1.27.1
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v2 v2.4.0
 	mvdan.cc/sh/v3 v3.3.1
 )
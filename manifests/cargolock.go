@@ -0,0 +1,48 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+type cargoLock struct {
+	Package []struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+		Source  string `toml:"source"`
+	} `toml:"package"`
+}
+
+func parseCargoLock(content []byte) ([]Dependency, error) {
+	var lock cargoLock
+	if _, err := toml.Decode(string(content), &lock); err != nil {
+		return nil, fmt.Errorf("toml.Decode: %w", err)
+	}
+	ret := make([]Dependency, 0, len(lock.Package))
+	for _, pkg := range lock.Package {
+		// Packages without a source are part of the local workspace.
+		if pkg.Source == "" {
+			continue
+		}
+		ret = append(ret, Dependency{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+		})
+	}
+	return ret, nil
+}
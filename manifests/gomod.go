@@ -0,0 +1,58 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+func parseGoMod(content []byte) ([]Dependency, error) {
+	f, err := modfile.ParseLax(GoMod, content, nil)
+	if err != nil {
+		return nil, fmt.Errorf("modfile.ParseLax: %w", err)
+	}
+	ret := make([]Dependency, 0, len(f.Require))
+	for _, require := range f.Require {
+		ret = append(ret, Dependency{
+			Name:    require.Mod.Path,
+			Version: require.Mod.Version,
+		})
+	}
+	return ret, nil
+}
+
+// parseModulesTxt parses the vendor/modules.txt of a Go module, which lists all the
+// vendored modules, including indirect dependencies, as `# path version` lines.
+func parseModulesTxt(content []byte) ([]Dependency, error) {
+	ret := []Dependency{}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		// Other lines are the packages of the module or `## explicit` annotations.
+		const minFields = 2
+		if len(fields) < minFields || fields[0] != "#" {
+			continue
+		}
+		dep := Dependency{Name: fields[1]}
+		// Replaced modules are listed as `# path [version] => replacement`.
+		if len(fields) > minFields && fields[2] != "=>" {
+			dep.Version = fields[2]
+		}
+		ret = append(ret, dep)
+	}
+	return ret, nil
+}
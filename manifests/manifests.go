@@ -0,0 +1,110 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package manifests parses dependency manifests and lockfiles.
+package manifests
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/ossf/scorecard/v2/resolvers"
+)
+
+// Supported manifest file names.
+const (
+	GoMod        = "go.mod"
+	ModulesTxt   = "modules.txt"
+	PackageLock  = "package-lock.json"
+	Requirements = "requirements.txt"
+	CargoLock    = "Cargo.lock"
+)
+
+// ErrUnsupportedManifest indicates there is no parser for the manifest.
+var ErrUnsupportedManifest = errors.New("unsupported manifest")
+
+// Dependency is a package a manifest depends on.
+type Dependency struct {
+	// Ecosystem is one of the ecosystems supported by the resolvers package.
+	Ecosystem string
+	Name      string
+	Version   string
+}
+
+type parser struct {
+	parse     func(content []byte) ([]Dependency, error)
+	ecosystem string
+}
+
+var parsers = map[string]parser{
+	GoMod:        {parseGoMod, resolvers.GoModules},
+	ModulesTxt:   {parseModulesTxt, resolvers.GoModules},
+	PackageLock:  {parsePackageLock, resolvers.NPM},
+	Requirements: {parseRequirements, resolvers.PyPI},
+	CargoLock:    {parseCargoLock, resolvers.Crates},
+}
+
+// Names returns the file names of all supported manifests, sorted.
+func Names() []string {
+	ret := make([]string, 0, len(parsers))
+	for name := range parsers {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// ParseFile reads the manifest at filename and returns its dependencies.
+// The parser is picked based on the base name of the file.
+func ParseFile(filename string) ([]Dependency, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+	return Parse(filepath.Base(filename), content)
+}
+
+// Parse returns the dependencies of a manifest, given its file name and content.
+// Dependencies are de-duplicated and sorted by name and version.
+func Parse(name string, content []byte) ([]Dependency, error) {
+	p, ok := parsers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s. Expected one of %v", ErrUnsupportedManifest, name, Names())
+	}
+	deps, err := p.parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", name, err)
+	}
+
+	seen := make(map[Dependency]bool, len(deps))
+	ret := make([]Dependency, 0, len(deps))
+	for _, dep := range deps {
+		dep.Ecosystem = p.ecosystem
+		if seen[dep] {
+			continue
+		}
+		seen[dep] = true
+		ret = append(ret, dep)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Name != ret[j].Name {
+			return ret[i].Name < ret[j].Name
+		}
+		return ret[i].Version < ret[j].Version
+	})
+	return ret, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v2/resolvers"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name     string
+		filename string
+		expected []Dependency
+	}{
+		{
+			name:     "GoMod",
+			filename: "testdata/go.mod",
+			expected: []Dependency{
+				{Ecosystem: resolvers.GoModules, Name: "github.com/google/go-cmp", Version: "v0.5.6"},
+				{Ecosystem: resolvers.GoModules, Name: "go.uber.org/zap", Version: "v1.18.1"},
+				{Ecosystem: resolvers.GoModules, Name: "golang.org/x/mod", Version: "v0.4.2"},
+			},
+		},
+		{
+			name:     "ModulesTxt",
+			filename: "testdata/modules.txt",
+			expected: []Dependency{
+				{Ecosystem: resolvers.GoModules, Name: "github.com/google/go-cmp", Version: "v0.5.6"},
+				{Ecosystem: resolvers.GoModules, Name: "go.uber.org/zap", Version: "v1.18.1"},
+				{Ecosystem: resolvers.GoModules, Name: "golang.org/x/mod", Version: ""},
+				{Ecosystem: resolvers.GoModules, Name: "k8s.io/api", Version: "v0.0.0"},
+			},
+		},
+		{
+			name:     "PackageLock",
+			filename: "testdata/package-lock.json",
+			expected: []Dependency{
+				{Ecosystem: resolvers.NPM, Name: "@angular/core", Version: "12.1.0"},
+				{Ecosystem: resolvers.NPM, Name: "left-pad", Version: "1.3.0"},
+				{Ecosystem: resolvers.NPM, Name: "tslib", Version: "2.3.0"},
+			},
+		},
+		{
+			name:     "Requirements",
+			filename: "testdata/requirements.txt",
+			expected: []Dependency{
+				{Ecosystem: resolvers.PyPI, Name: "Django", Version: ""},
+				{Ecosystem: resolvers.PyPI, Name: "flask", Version: ""},
+				{Ecosystem: resolvers.PyPI, Name: "numpy", Version: "1.21.1"},
+				{Ecosystem: resolvers.PyPI, Name: "requests", Version: "2.26.0"},
+			},
+		},
+		{
+			name:     "CargoLock",
+			filename: "testdata/Cargo.lock",
+			expected: []Dependency{
+				{Ecosystem: resolvers.Crates, Name: "rand", Version: "0.8.4"},
+				{Ecosystem: resolvers.Crates, Name: "serde", Version: "1.0.126"},
+			},
+		},
+	}
+	for _, tt := range testcases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			deps, err := ParseFile(tt.filename)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, deps); diff != "" {
+				t.Errorf("unexpected dependencies (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePackageLockV1(t *testing.T) {
+	t.Parallel()
+	content, err := ioutil.ReadFile("testdata/package-lock-v1.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deps, err := Parse(PackageLock, content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Dependency{
+		{Ecosystem: resolvers.NPM, Name: "@angular/core", Version: "12.1.0"},
+		{Ecosystem: resolvers.NPM, Name: "left-pad", Version: "1.3.0"},
+		{Ecosystem: resolvers.NPM, Name: "tslib", Version: "2.3.0"},
+	}
+	if diff := cmp.Diff(expected, deps); diff != "" {
+		t.Errorf("unexpected dependencies (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseUnsupported(t *testing.T) {
+	t.Parallel()
+	if _, err := Parse(filepath.Base("pom.xml"), nil); !errors.Is(err, ErrUnsupportedManifest) {
+		t.Errorf("expected ErrUnsupportedManifest, got: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const nodeModules = "node_modules/"
+
+// packageLockDependency is an entry of the lockfileVersion 1 `dependencies` tree.
+type packageLockDependency struct {
+	Dependencies map[string]packageLockDependency `json:"dependencies"`
+	Version      string                           `json:"version"`
+}
+
+type packageLock struct {
+	// Packages is used by lockfileVersion 2 and later, keyed by install path.
+	Packages map[string]struct {
+		Version string `json:"version"`
+		Link    bool   `json:"link"`
+	} `json:"packages"`
+	// Dependencies is used by lockfileVersion 1, keyed by package name.
+	Dependencies map[string]packageLockDependency `json:"dependencies"`
+}
+
+func parsePackageLock(content []byte) ([]Dependency, error) {
+	var lock packageLock
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	ret := []Dependency{}
+	if len(lock.Packages) > 0 {
+		for path, pkg := range lock.Packages {
+			// The empty path is the root project itself.
+			if path == "" || pkg.Link {
+				continue
+			}
+			index := strings.LastIndex(path, nodeModules)
+			if index < 0 {
+				continue
+			}
+			ret = append(ret, Dependency{
+				Name:    path[index+len(nodeModules):],
+				Version: pkg.Version,
+			})
+		}
+		return ret, nil
+	}
+	return appendPackageLockDependencies(ret, lock.Dependencies), nil
+}
+
+func appendPackageLockDependencies(ret []Dependency, deps map[string]packageLockDependency) []Dependency {
+	for name, dep := range deps {
+		ret = append(ret, Dependency{
+			Name:    name,
+			Version: dep.Version,
+		})
+		ret = appendPackageLockDependencies(ret, dep.Dependencies)
+	}
+	return ret
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Matches the project name and, for pinned requirements, the version of a
+// requirement specifier, e.g. `requests[security]==2.26.0 ; python_version > "3"`.
+var requirementSpecifier = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(?:===?\s*([^\s;,]+))?`)
+
+func parseRequirements(content []byte) ([]Dependency, error) {
+	// Join lines continued with a trailing backslash.
+	text := strings.ReplaceAll(string(content), "\\\n", " ")
+	ret := []Dependency{}
+	for _, line := range strings.Split(text, "\n") {
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+		line = strings.TrimSpace(line)
+		// Skip options such as `-r other.txt` or `-e .`, and direct URLs.
+		if line == "" || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+		m := requirementSpecifier.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		ret = append(ret, Dependency{
+			Name:    m[1],
+			Version: m[2],
+		})
+	}
+	return ret, nil
+}
@@ -0,0 +1,12 @@
+module example.com/app
+
+go 1.16
+
+require (
+	github.com/google/go-cmp v0.5.6
+	go.uber.org/zap v1.18.1 // indirect
+)
+
+require golang.org/x/mod v0.4.2
+
+replace github.com/google/go-cmp => ../go-cmp
@@ -0,0 +1,159 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+
+	"github.com/ossf/scorecard/v2/checker"
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+// Badge colours, as used by shields.io.
+const (
+	BadgeColorBrightGreen = "#4c1"
+	BadgeColorGreen       = "#97ca00"
+	BadgeColorYellow      = "#dfb317"
+	BadgeColorOrange      = "#fe7d37"
+	BadgeColorRed         = "#e05d44"
+	BadgeColorGrey        = "#9f9f9f"
+)
+
+// badgeLabel is the label of badges for the aggregate score.
+const badgeLabel = "scorecard"
+
+// Badge is a shields.io-style badge, e.g. `scorecard | 7.5`.
+type Badge struct {
+	Label   string
+	Message string
+	Color   string
+}
+
+// AggregateScore returns the average score of the conclusive checks of the result,
+// and false if no check was conclusive.
+func (r *ScorecardResult) AggregateScore() (float64, bool) {
+	total, n := 0, 0
+	for i := range r.Checks {
+		if r.Checks[i].Score == checker.InconclusiveResultScore {
+			continue
+		}
+		total += r.Checks[i].Score
+		n++
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return float64(total) / float64(n), true
+}
+
+// AggregateBadge returns the badge for the aggregate score of the result.
+func (r *ScorecardResult) AggregateBadge() Badge {
+	score, ok := r.AggregateScore()
+	if !ok {
+		return Badge{Label: badgeLabel, Message: "?", Color: BadgeColorGrey}
+	}
+	// nolint: gomnd
+	score = math.Floor(score*10) / 10
+	return Badge{Label: badgeLabel, Message: fmt.Sprintf("%.1f", score), Color: ScoreColor(score)}
+}
+
+// CheckBadge returns the badge for the score of a check.
+func CheckBadge(check *checker.CheckResult) Badge {
+	if check.Score == checker.InconclusiveResultScore {
+		return Badge{Label: check.Name, Message: "?", Color: BadgeColorGrey}
+	}
+	return Badge{
+		Label:   check.Name,
+		Message: fmt.Sprintf("%d/%d", check.Score, checker.MaxResultScore),
+		Color:   ScoreColor(float64(check.Score)),
+	}
+}
+
+// ScoreColor returns the badge colour for a score between 0 and 10.
+func ScoreColor(score float64) string {
+	// nolint: gomnd
+	switch {
+	case score >= 9:
+		return BadgeColorBrightGreen
+	case score >= 7:
+		return BadgeColorGreen
+	case score >= 5:
+		return BadgeColorYellow
+	case score >= 3:
+		return BadgeColorOrange
+	default:
+		return BadgeColorRed
+	}
+}
+
+// AsSVG outputs the badge for the aggregate score of the result as an SVG image.
+func (r *ScorecardResult) AsSVG(writer io.Writer) error {
+	return r.AggregateBadge().WriteSVG(writer)
+}
+
+// WriteSVG renders the badge as an SVG image in the flat shields.io style.
+func (b Badge) WriteSVG(writer io.Writer) error {
+	// nolint: gomnd
+	const padding = 10
+	labelWidth := textWidth(b.Label) + padding
+	messageWidth := textWidth(b.Message) + padding
+	width := labelWidth + messageWidth
+	label, message := html.EscapeString(b.Label), html.EscapeString(b.Message)
+	color := html.EscapeString(b.Color)
+	_, err := fmt.Fprintf(writer, badgeTemplate,
+		width, label, message, label, message,
+		width,
+		labelWidth, labelWidth, messageWidth, color, width,
+		labelWidth/2, label, labelWidth/2, label,
+		labelWidth+messageWidth/2, message, labelWidth+messageWidth/2, message)
+	if err != nil {
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("fmt.Fprintf: %v", err))
+	}
+	return nil
+}
+
+// textWidth approximates the width in pixels of s in 11px Verdana.
+func textWidth(s string) int {
+	width := 0.0
+	for _, r := range s {
+		switch r {
+		case 'i', 'j', 'l', '.', ',', ':', ';', '!', '|', '\'', 'I':
+			width += 3.5
+		case 'f', 'r', 't', '(', ')', '/', ' ', '-':
+			width += 5
+		case 'm', 'w', 'M', 'W':
+			width += 10
+		default:
+			width += 7
+		}
+	}
+	return int(math.Ceil(width))
+}
+
+const badgeTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">
+<title>%s: %s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>
+<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>
+</g>
+</svg>
+`
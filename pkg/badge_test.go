@@ -0,0 +1,92 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/ossf/scorecard/v2/checker"
+)
+
+func TestAggregateBadge(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		scores []int
+		want   Badge
+	}{
+		{
+			name:   "high",
+			scores: []int{10, 9, checker.InconclusiveResultScore},
+			want:   Badge{Label: "scorecard", Message: "9.5", Color: BadgeColorBrightGreen},
+		},
+		{
+			name:   "rounded down",
+			scores: []int{10, 4, 4},
+			want:   Badge{Label: "scorecard", Message: "6.0", Color: BadgeColorYellow},
+		},
+		{
+			name:   "low",
+			scores: []int{0, 2},
+			want:   Badge{Label: "scorecard", Message: "1.0", Color: BadgeColorRed},
+		},
+		{
+			name:   "inconclusive",
+			scores: []int{checker.InconclusiveResultScore},
+			want:   Badge{Label: "scorecard", Message: "?", Color: BadgeColorGrey},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := ScorecardResult{}
+			for _, score := range tt.scores {
+				result.Checks = append(result.Checks, checker.CheckResult{Score: score})
+			}
+			if got := result.AggregateBadge(); got != tt.want {
+				t.Errorf("AggregateBadge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBadgeWriteSVG(t *testing.T) {
+	t.Parallel()
+	badge := CheckBadge(&checker.CheckResult{Name: "Code-Review<&>", Score: 7})
+	if badge.Message != "7/10" || badge.Color != BadgeColorGreen {
+		t.Errorf("unexpected badge: %+v", badge)
+	}
+	var buf bytes.Buffer
+	if err := badge.WriteSVG(&buf); err != nil {
+		t.Fatalf("WriteSVG: %v", err)
+	}
+	var svg struct {
+		XMLName xml.Name
+		Title   string `xml:"title"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &svg); err != nil {
+		t.Fatalf("badge is not valid XML: %v\n%s", err, buf.String())
+	}
+	if svg.XMLName.Local != "svg" || svg.Title != "Code-Review<&>: 7/10" {
+		t.Errorf("unexpected badge: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), BadgeColorGreen) {
+		t.Errorf("badge does not use its colour: %s", buf.String())
+	}
+}
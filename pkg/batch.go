@@ -0,0 +1,91 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/clients"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+// BatchResult is the outcome of running Scorecard on a single repo as part of a batch.
+type BatchResult struct {
+	Repo   repos.RepoURL
+	Result ScorecardResult
+	Err    error
+}
+
+// RepoClientFactory returns a new RepoClient. RunScorecardsBatch uses it to
+// create one RepoClient per worker, since a RepoClient holds per-repo state.
+type RepoClientFactory func() clients.RepoClient
+
+// RunScorecardsBatch runs enabled Scorecard checks on each RepoURL, scoring at most
+// `parallelism` repos concurrently. The HTTP and GitHub clients are shared across all
+// workers. A failure to score one repo is recorded in its BatchResult and does not stop
+// the batch. Results are returned in the same order as repoURLs.
+func RunScorecardsBatch(ctx context.Context,
+	repoURLs []repos.RepoURL,
+	checksToRun checker.CheckNameToFnMap,
+	parallelism int,
+	newRepoClient RepoClientFactory,
+	httpClient *http.Client,
+	githubClient *github.Client,
+	graphClient *githubv4.Client) []BatchResult {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	if parallelism > len(repoURLs) {
+		parallelism = len(repoURLs)
+	}
+
+	ret := make([]BatchResult, len(repoURLs))
+	indexCh := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repoClient := newRepoClient()
+			// nolint: errcheck
+			defer repoClient.Close()
+			for index := range indexCh {
+				repo := repoURLs[index]
+				result, err := RunScorecards(ctx, repo, checksToRun, repoClient,
+					httpClient, githubClient, graphClient)
+				if err == nil {
+					result.Metadata = append(result.Metadata, repo.Metadata...)
+				}
+				ret[index] = BatchResult{
+					Repo:   repo,
+					Result: result,
+					Err:    err,
+				}
+			}
+		}()
+	}
+	for i := range repoURLs {
+		indexCh <- i
+	}
+	close(indexCh)
+	wg.Wait()
+	return ret
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/clients"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+var errInitRepo = errors.New("init repo failed")
+
+// fakeRepoClient implements clients.RepoClient and fails InitRepo for owner "unreachable".
+type fakeRepoClient struct {
+	clients.RepoClient
+}
+
+func (client *fakeRepoClient) InitRepo(owner, repo string) error {
+	if owner == "unreachable" {
+		return clients.NewRepoUnavailableError(errInitRepo)
+	}
+	return nil
+}
+
+func (client *fakeRepoClient) Close() error {
+	return nil
+}
+
+func TestRunScorecardsBatch(t *testing.T) {
+	t.Parallel()
+	repoURLs := []repos.RepoURL{
+		{Host: "github.com", Owner: "owner1", Repo: "repo1", Metadata: []string{"meta"}},
+		{Host: "github.com", Owner: "unreachable", Repo: "repo2"},
+		{Host: "github.com", Owner: "owner3", Repo: "repo3"},
+	}
+	checksToRun := checker.CheckNameToFnMap{
+		"Fake-Check": func(c *checker.CheckRequest) checker.CheckResult {
+			return checker.CreateMaxScoreResult("Fake-Check", c.Owner+"/"+c.Repo)
+		},
+	}
+	newRepoClient := func() clients.RepoClient {
+		return &fakeRepoClient{}
+	}
+
+	results := RunScorecardsBatch(context.Background(), repoURLs, checksToRun, 2, newRepoClient, nil, nil, nil)
+	if len(results) != len(repoURLs) {
+		t.Fatalf("expected %d results, got %d", len(repoURLs), len(results))
+	}
+	for i, result := range results {
+		if result.Repo.URL() != repoURLs[i].URL() {
+			t.Errorf("result %d: expected repo %s, got %s", i, repoURLs[i].URL(), result.Repo.URL())
+		}
+	}
+
+	if results[0].Err != nil {
+		t.Errorf("unexpected error: %v", results[0].Err)
+	}
+	if !cmp.Equal(results[0].Result.Metadata, []string{"meta"}) {
+		t.Errorf("unexpected metadata: %v", results[0].Result.Metadata)
+	}
+	if len(results[0].Result.Checks) != 1 || results[0].Result.Checks[0].Reason != "owner1/repo1" {
+		t.Errorf("unexpected checks: %v", results[0].Result.Checks)
+	}
+
+	var errRepoUnavailable *clients.ErrRepoUnavailable
+	if !errors.As(results[1].Err, &errRepoUnavailable) {
+		t.Errorf("expected ErrRepoUnavailable, got: %v", results[1].Err)
+	}
+
+	if results[2].Err != nil {
+		t.Errorf("unexpected error: %v", results[2].Err)
+	}
+}
+
+func TestResultsAsCSV(t *testing.T) {
+	t.Parallel()
+	results := []ScorecardResult{
+		{
+			Repo:   "github.com/owner1/repo1",
+			Checks: []checker.CheckResult{{Name: "Check", Pass: true, Confidence: 10}},
+		},
+		{
+			Repo:   "github.com/owner2/repo2",
+			Checks: []checker.CheckResult{{Name: "Check", Pass: false, Confidence: 5}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := ResultsAsCSV(results, false, zapcore.InfoLevel, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Repository,Check_Pass,Check_Confidence\n" +
+		"github.com/owner1/repo1,true,10\n" +
+		"github.com/owner2/repo2,false,5\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected CSV output (-want +got):\n%s", diff)
+	}
+}
+
+func TestResultsAsCSVDifferentChecks(t *testing.T) {
+	t.Parallel()
+	results := []ScorecardResult{
+		{
+			Repo: "github.com/owner1/repo1",
+			Checks: []checker.CheckResult{
+				{Name: "A", Pass: true, Confidence: 10, Details: []string{"a1", "a2"}},
+			},
+		},
+		{
+			Repo: "github.com/owner2/repo2",
+			Checks: []checker.CheckResult{
+				{Name: "B", Pass: false, Confidence: 5, Details: []string{"b"}},
+				{Name: "A", Pass: false, Confidence: 7},
+			},
+		},
+		{Repo: "github.com/owner3/repo3"},
+	}
+	var buf bytes.Buffer
+	if err := ResultsAsCSV(results, true, zapcore.InfoLevel, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Repository,A_Pass,A_Confidence,A_Details,B_Pass,B_Confidence,B_Details\n" +
+		"github.com/owner1/repo1,true,10,\"a1\na2\",,,\n" +
+		"github.com/owner2/repo2,false,7,,false,5,b\n" +
+		"github.com/owner3/repo3,,,,,,\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected CSV output (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"go.uber.org/zap/zapcore"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+// DependencyResult is the result for a single dependency of a manifest.
+type DependencyResult struct {
+	Ecosystem string
+	Name      string
+	Version   string
+	// Result is the Scorecard result of the dependency's source repository.
+	Result ScorecardResult
+}
+
+// UnresolvedDependency is a dependency which could not be checked.
+type UnresolvedDependency struct {
+	Ecosystem string
+	Name      string
+	Version   string
+	Reason    string
+}
+
+// DependenciesResult is returned on a successful Scorecard run over the dependencies of a manifest.
+type DependenciesResult struct {
+	Manifest     string
+	Date         string
+	Dependencies []DependencyResult
+	Unresolved   []UnresolvedDependency
+	// Rollup is computed over the distinct source repositories of Dependencies.
+	Rollup []CheckRollup
+}
+
+// AsJSON outputs the DependenciesResult in JSON format with a newline at the end.
+func (r *DependenciesResult) AsJSON(showDetails bool, logLevel zapcore.Level, writer io.Writer) error {
+	out := *r
+	if !showDetails {
+		out.Dependencies = make([]DependencyResult, len(r.Dependencies))
+		for i := range r.Dependencies {
+			out.Dependencies[i] = r.Dependencies[i]
+			out.Dependencies[i].Result = r.Dependencies[i].Result.withoutDetails()
+		}
+	}
+	if err := json.NewEncoder(writer).Encode(out); err != nil {
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("encoder.Encode: %v", err))
+	}
+	return nil
+}
+
+// AsCSV outputs one record per checked dependency, with the package columns
+// preceding the usual ScorecardResult columns, as in ResultsAsCSV.
+func (r *DependenciesResult) AsCSV(showDetails bool, logLevel zapcore.Level, writer io.Writer) error {
+	if len(r.Dependencies) == 0 {
+		return nil
+	}
+	results := make([]ScorecardResult, len(r.Dependencies))
+	for i := range r.Dependencies {
+		results[i] = r.Dependencies[i].Result
+	}
+	checkNames := csvCheckNames(results)
+	w := csv.NewWriter(writer)
+	columns := append([]string{"Ecosystem", "Package", "Version"}, csvColumns(checkNames, showDetails)...)
+	fmt.Fprintf(writer, "%s\n", strings.Join(columns, ","))
+	for i := range r.Dependencies {
+		dep := &r.Dependencies[i]
+		record := append([]string{dep.Ecosystem, dep.Name, dep.Version}, dep.Result.csvRecord(checkNames, showDetails)...)
+		if err := w.Write(record); err != nil {
+			//nolint:wrapcheck
+			return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("csv.Write: %v", err))
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("csv.Flush: %v", err))
+	}
+	return nil
+}
+
+// AsString outputs the DependenciesResult as a per-dependency table of scores,
+// followed by the rollup table and the dependencies which could not be checked.
+func (r *DependenciesResult) AsString(showDetails bool, logLevel zapcore.Level, writer io.Writer) error {
+	if len(r.Dependencies) == 0 {
+		fmt.Fprintf(writer, "No dependencies checked for %s\n", r.Manifest)
+	} else {
+		checkNames := rollupCheckNames(r.Rollup)
+		depTable := tablewriter.NewWriter(writer)
+		depTable.SetHeader(append([]string{"Package", "Version", "Repo"}, checkNames...))
+		for i := range r.Dependencies {
+			dep := &r.Dependencies[i]
+			row := append([]string{dep.Name, dep.Version, dep.Result.Repo}, checkScores(&dep.Result, checkNames)...)
+			depTable.Append(row)
+		}
+		depTable.SetAutoFormatHeaders(false)
+		depTable.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		depTable.SetCenterSeparator("|")
+		depTable.SetAlignment(tablewriter.ALIGN_LEFT)
+		depTable.Render()
+
+		fmt.Fprintf(writer, "\nROLLUP for %s (%d dependencies)\n-------\n", r.Manifest, len(r.Dependencies))
+		renderRollup(r.Rollup, writer)
+	}
+
+	if len(r.Unresolved) == 0 {
+		return nil
+	}
+	fmt.Fprintf(writer, "\nUNRESOLVED dependencies of %s (%d)\n-------\n", r.Manifest, len(r.Unresolved))
+	unresolvedTable := tablewriter.NewWriter(writer)
+	unresolvedTable.SetHeader([]string{"Package", "Version", "Reason"})
+	for _, dep := range r.Unresolved {
+		unresolvedTable.Append([]string{dep.Name, dep.Version, dep.Reason})
+	}
+	unresolvedTable.SetAutoFormatHeaders(false)
+	unresolvedTable.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	unresolvedTable.SetCenterSeparator("|")
+	unresolvedTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	unresolvedTable.Render()
+	return nil
+}
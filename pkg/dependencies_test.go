@@ -0,0 +1,66 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ossf/scorecard/v2/checker"
+)
+
+func TestDependenciesResultAsCSV(t *testing.T) {
+	t.Parallel()
+	result := DependenciesResult{
+		Manifest: "go.mod",
+		Dependencies: []DependencyResult{
+			{
+				Ecosystem: "go",
+				Name:      "go.uber.org/zap",
+				Version:   "v1.18.1",
+				Result: ScorecardResult{
+					Repo:   "github.com/uber-go/zap",
+					Checks: []checker.CheckResult{{Name: "Check-A", Pass: true, Confidence: 10}},
+				},
+			},
+			{
+				Ecosystem: "go",
+				Name:      "go.uber.org/zap/exp",
+				Version:   "v0.1.0",
+				Result: ScorecardResult{
+					Repo:   "github.com/uber-go/zap",
+					Checks: []checker.CheckResult{{Name: "Check-A", Pass: true, Confidence: 10}},
+				},
+			},
+		},
+		Unresolved: []UnresolvedDependency{
+			{Ecosystem: "go", Name: "example.com/private", Version: "v1.0.0", Reason: "package not found"},
+		},
+	}
+	expected := `Ecosystem,Package,Version,Repository,Check-A_Pass,Check-A_Confidence
+go,go.uber.org/zap,v1.18.1,github.com/uber-go/zap,true,10
+go,go.uber.org/zap/exp,v0.1.0,github.com/uber-go/zap,true,10
+`
+	var buf bytes.Buffer
+	if err := result.AsCSV(false, zapcore.InfoLevel, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected CSV (-want +got):\n%s", diff)
+	}
+}
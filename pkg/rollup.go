@@ -0,0 +1,216 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ossf/scorecard/v2/checker"
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+// RepoScore is the score a single repo got for a check.
+type RepoScore struct {
+	Repo  string
+	Score int
+}
+
+// CheckRollup summarizes the results of a single check across many repos.
+type CheckRollup struct {
+	Name string
+	// Distribution maps each score to the number of repos which got it.
+	// Inconclusive results are counted under checker.InconclusiveResultScore.
+	Distribution map[int]int
+	// AverageScore is computed over conclusive results only.
+	AverageScore float64
+	// WorstOffenders lists the repos with the lowest conclusive scores, lowest first.
+	WorstOffenders []RepoScore
+}
+
+// OrgResult is returned on a successful Scorecard run over an organisation.
+type OrgResult struct {
+	Org    string
+	Date   string
+	Repos  []ScorecardResult
+	Rollup []CheckRollup
+}
+
+// RollupResults computes a CheckRollup for every check found in results,
+// keeping at most maxOffenders worst offenders per check.
+func RollupResults(results []ScorecardResult, maxOffenders int) []CheckRollup {
+	scoresByCheck := make(map[string][]RepoScore)
+	for i := range results {
+		for j := range results[i].Checks {
+			check := &results[i].Checks[j]
+			scoresByCheck[check.Name] = append(scoresByCheck[check.Name], RepoScore{
+				Repo:  results[i].Repo,
+				Score: check.Score,
+			})
+		}
+	}
+
+	ret := make([]CheckRollup, 0, len(scoresByCheck))
+	for name, scores := range scoresByCheck {
+		rollup := CheckRollup{
+			Name:         name,
+			Distribution: make(map[int]int),
+		}
+		conclusive := make([]RepoScore, 0, len(scores))
+		total := 0
+		for _, s := range scores {
+			rollup.Distribution[s.Score]++
+			if s.Score == checker.InconclusiveResultScore {
+				continue
+			}
+			conclusive = append(conclusive, s)
+			total += s.Score
+		}
+		if len(conclusive) > 0 {
+			rollup.AverageScore = float64(total) / float64(len(conclusive))
+		}
+		sort.Slice(conclusive, func(i, j int) bool {
+			if conclusive[i].Score != conclusive[j].Score {
+				return conclusive[i].Score < conclusive[j].Score
+			}
+			return conclusive[i].Repo < conclusive[j].Repo
+		})
+		if len(conclusive) > maxOffenders {
+			conclusive = conclusive[:maxOffenders]
+		}
+		rollup.WorstOffenders = conclusive
+		ret = append(ret, rollup)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// AsJSON outputs the OrgResult in JSON format with a newline at the end.
+func (r *OrgResult) AsJSON(showDetails bool, logLevel zapcore.Level, writer io.Writer) error {
+	out := *r
+	if !showDetails {
+		out.Repos = make([]ScorecardResult, len(r.Repos))
+		for i := range r.Repos {
+			out.Repos[i] = r.Repos[i].withoutDetails()
+		}
+	}
+	if err := json.NewEncoder(writer).Encode(out); err != nil {
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("encoder.Encode: %v", err))
+	}
+	return nil
+}
+
+// AsString outputs the OrgResult as a per-repo table of scores followed by the rollup table.
+func (r *OrgResult) AsString(showDetails bool, logLevel zapcore.Level, writer io.Writer) error {
+	if len(r.Repos) == 0 {
+		fmt.Fprintf(writer, "No repositories checked for %s\n", r.Org)
+		return nil
+	}
+
+	checkNames := rollupCheckNames(r.Rollup)
+	repoTable := tablewriter.NewWriter(writer)
+	repoTable.SetHeader(append([]string{"Repo"}, checkNames...))
+	for i := range r.Repos {
+		repoTable.Append(append([]string{r.Repos[i].Repo}, checkScores(&r.Repos[i], checkNames)...))
+	}
+	repoTable.SetAutoFormatHeaders(false)
+	repoTable.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	repoTable.SetCenterSeparator("|")
+	repoTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	repoTable.Render()
+
+	fmt.Fprintf(writer, "\nROLLUP for %s (%d repositories)\n-------\n", r.Org, len(r.Repos))
+	renderRollup(r.Rollup, writer)
+	return nil
+}
+
+// renderRollup outputs the rollup as a table with one row per check.
+func renderRollup(rollups []CheckRollup, writer io.Writer) {
+	rollupTable := tablewriter.NewWriter(writer)
+	rollupTable.SetHeader([]string{"Name", "Average", "Distribution", "Worst Offenders"})
+	for i := range rollups {
+		rollup := &rollups[i]
+		offenders := make([]string, len(rollup.WorstOffenders))
+		for j, offender := range rollup.WorstOffenders {
+			offenders[j] = fmt.Sprintf("%s (%d)", offender.Repo, offender.Score)
+		}
+		rollupTable.Append([]string{
+			rollup.Name,
+			fmt.Sprintf("%.1f", rollup.AverageScore),
+			distributionToString(rollup.Distribution),
+			strings.Join(offenders, "\n"),
+		})
+	}
+	rollupTable.SetAutoFormatHeaders(false)
+	rollupTable.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	rollupTable.SetRowSeparator("-")
+	rollupTable.SetRowLine(true)
+	rollupTable.SetCenterSeparator("|")
+	rollupTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	rollupTable.Render()
+}
+
+// checkScores returns the score of each named check as a string, in order.
+func checkScores(result *ScorecardResult, checkNames []string) []string {
+	scores := make(map[string]int)
+	for i := range result.Checks {
+		scores[result.Checks[i].Name] = result.Checks[i].Score
+	}
+	ret := make([]string, 0, len(checkNames))
+	for _, name := range checkNames {
+		score, ok := scores[name]
+		ret = append(ret, scoreToString(score, ok))
+	}
+	return ret
+}
+
+// rollupCheckNames returns the names of the checks in the rollup.
+func rollupCheckNames(rollups []CheckRollup) []string {
+	ret := make([]string, 0, len(rollups))
+	for i := range rollups {
+		ret = append(ret, rollups[i].Name)
+	}
+	return ret
+}
+
+func scoreToString(score int, ok bool) string {
+	if !ok || score == checker.InconclusiveResultScore {
+		return "?"
+	}
+	return fmt.Sprintf("%d", score)
+}
+
+// distributionToString renders a distribution as `score: count` pairs, highest score first.
+func distributionToString(distribution map[int]int) string {
+	scores := make([]int, 0, len(distribution))
+	for score := range distribution {
+		scores = append(scores, score)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(scores)))
+	parts := make([]string, len(scores))
+	for i, score := range scores {
+		parts[i] = fmt.Sprintf("%s: %d", scoreToString(score, true), distribution[score])
+	}
+	return strings.Join(parts, "\n")
+}
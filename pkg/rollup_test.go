@@ -0,0 +1,73 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v2/checker"
+)
+
+func TestRollupResults(t *testing.T) {
+	t.Parallel()
+	results := []ScorecardResult{
+		{
+			Repo: "github.com/org/repo1",
+			Checks: []checker.CheckResult{
+				{Name: "Check-A", Score: 10},
+				{Name: "Check-B", Score: checker.InconclusiveResultScore},
+			},
+		},
+		{
+			Repo: "github.com/org/repo2",
+			Checks: []checker.CheckResult{
+				{Name: "Check-A", Score: 2},
+				{Name: "Check-B", Score: 4},
+			},
+		},
+		{
+			Repo: "github.com/org/repo3",
+			Checks: []checker.CheckResult{
+				{Name: "Check-A", Score: 2},
+				{Name: "Check-B", Score: 8},
+			},
+		},
+	}
+	expected := []CheckRollup{
+		{
+			Name:         "Check-A",
+			Distribution: map[int]int{10: 1, 2: 2},
+			AverageScore: 14.0 / 3,
+			WorstOffenders: []RepoScore{
+				{Repo: "github.com/org/repo2", Score: 2},
+				{Repo: "github.com/org/repo3", Score: 2},
+			},
+		},
+		{
+			Name:         "Check-B",
+			Distribution: map[int]int{checker.InconclusiveResultScore: 1, 4: 1, 8: 1},
+			AverageScore: 6,
+			WorstOffenders: []RepoScore{
+				{Repo: "github.com/org/repo2", Score: 4},
+				{Repo: "github.com/org/repo3", Score: 8},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, RollupResults(results, 2)); diff != "" {
+		t.Errorf("unexpected rollup (-want +got):\n%s", diff)
+	}
+}
@@ -39,15 +39,59 @@ func logStats(ctx context.Context, startTime time.Time) {
 	opencensusstats.Record(ctx, stats.RepoRuntimeInSec.M(runTimeInSecs))
 }
 
+// CheckEventType is the type of a CheckEvent.
+type CheckEventType int
+
+const (
+	// CheckStarted is sent when a check starts running.
+	CheckStarted CheckEventType = iota
+	// CheckFinished is sent with the result of a check once it finished.
+	CheckFinished
+)
+
+// String returns `started` or `finished`.
+func (t CheckEventType) String() string {
+	switch t {
+	case CheckStarted:
+		return "started"
+	case CheckFinished:
+		return "finished"
+	default:
+		return fmt.Sprintf("CheckEventType(%d)", int(t))
+	}
+}
+
+// CheckEvent reports the progress of a check run by StreamScorecards.
+type CheckEvent struct {
+	Type CheckEventType
+	// Check is the name of the check.
+	Check string
+	// Start is when the check started running.
+	Start time.Time
+	// Duration is how long the check ran for, retries included. Only set on CheckFinished.
+	Duration time.Duration
+	// Result is the result of the check. Only set on CheckFinished.
+	Result checker.CheckResult
+}
+
+// CheckEventFunc is called with each CheckEvent of a run.
+type CheckEventFunc func(event CheckEvent)
+
 func runEnabledChecks(ctx context.Context,
 	repo repos.RepoURL, checksToRun checker.CheckNameToFnMap, repoClient clients.RepoClient,
 	httpClient *http.Client, githubClient *github.Client, graphClient *githubv4.Client,
-	resultsCh chan checker.CheckResult) {
+	eventsCh chan CheckEvent) {
+	// Checks build URLs from the host, so repos constructed without one are on github.com.
+	host := repo.Host
+	if host == "" {
+		host = "github.com"
+	}
 	request := checker.CheckRequest{
 		Ctx:         ctx,
 		Client:      githubClient,
 		RepoClient:  repoClient,
 		HTTPClient:  httpClient,
+		Host:        host,
 		Owner:       repo.Owner,
 		Repo:        repo.Repo,
 		GraphClient: graphClient,
@@ -64,44 +108,78 @@ func runEnabledChecks(ctx context.Context,
 				CheckName:    checkName,
 				CheckRequest: request,
 			}
-			resultsCh <- runner.Run(ctx, checkFn)
+			start := time.Now()
+			eventsCh <- CheckEvent{Type: CheckStarted, Check: checkName, Start: start}
+			result := runner.Run(ctx, checkFn)
+			eventsCh <- CheckEvent{
+				Type:     CheckFinished,
+				Check:    checkName,
+				Start:    start,
+				Duration: time.Since(start),
+				Result:   result,
+			}
 		}()
 	}
 	wg.Wait()
-	close(resultsCh)
+	close(eventsCh)
 }
 
-// RunScorecards runs enabled Scorecard checks on a RepoURL.
-func RunScorecards(ctx context.Context,
+// StreamScorecards runs enabled Scorecard checks on a RepoURL concurrently, and calls
+// onEvent as each check starts and finishes. Calls to onEvent are made one at a time
+// from the calling goroutine, so onEvent needs no locking, but it delays the other
+// events while it runs. StreamScorecards returns once all checks finished, or if the
+// repo cannot be initialized, in which case no check runs.
+func StreamScorecards(ctx context.Context,
 	repo repos.RepoURL,
 	checksToRun checker.CheckNameToFnMap,
 	repoClient clients.RepoClient,
 	httpClient *http.Client,
 	githubClient *github.Client,
-	graphClient *githubv4.Client) (ScorecardResult, error) {
+	graphClient *githubv4.Client,
+	onEvent CheckEventFunc) error {
 	ctx, err := tag.New(ctx, tag.Upsert(stats.Repo, repo.URL()))
 	if err != nil {
 		//nolint:wrapcheck
-		return ScorecardResult{}, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("tag.New: %v", err))
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("tag.New: %v", err))
 	}
 	defer logStats(ctx, time.Now())
 
 	if err := repoClient.InitRepo(repo.Owner, repo.Repo); err != nil {
 		// No need to call sce.Create() since InitRepo will do that for us.
 		//nolint:wrapcheck
-		return ScorecardResult{}, err
+		return err
 	}
 
+	eventsCh := make(chan CheckEvent)
+	go runEnabledChecks(ctx, repo, checksToRun, repoClient,
+		httpClient, githubClient, graphClient,
+		eventsCh)
+	for event := range eventsCh {
+		onEvent(event)
+	}
+	return nil
+}
+
+// RunScorecards runs enabled Scorecard checks on a RepoURL.
+func RunScorecards(ctx context.Context,
+	repo repos.RepoURL,
+	checksToRun checker.CheckNameToFnMap,
+	repoClient clients.RepoClient,
+	httpClient *http.Client,
+	githubClient *github.Client,
+	graphClient *githubv4.Client) (ScorecardResult, error) {
 	ret := ScorecardResult{
 		Repo: repo.URL(),
 		Date: time.Now().Format("2006-01-02"),
 	}
-	resultsCh := make(chan checker.CheckResult)
-	go runEnabledChecks(ctx, repo, checksToRun, repoClient,
-		httpClient, githubClient, graphClient,
-		resultsCh)
-	for result := range resultsCh {
-		ret.Checks = append(ret.Checks, result)
+	err := StreamScorecards(ctx, repo, checksToRun, repoClient, httpClient, githubClient, graphClient,
+		func(event CheckEvent) {
+			if event.Type == CheckFinished {
+				ret.Checks = append(ret.Checks, event.Result)
+			}
+		})
+	if err != nil {
+		return ScorecardResult{}, err
 	}
 	return ret, nil
 }
@@ -49,6 +49,16 @@ func (r *ScorecardResult) AsJSON(showDetails bool, logLevel zapcore.Level, write
 		}
 		return nil
 	}
+	if err := encoder.Encode(r.withoutDetails()); err != nil {
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("encoder.Encode: %v", err))
+	}
+	return nil
+}
+
+// withoutDetails returns a copy of the result which only keeps the fields
+// output when details are not requested.
+func (r *ScorecardResult) withoutDetails() ScorecardResult {
 	out := ScorecardResult{
 		Repo:     r.Repo,
 		Date:     r.Date,
@@ -61,37 +71,35 @@ func (r *ScorecardResult) AsJSON(showDetails bool, logLevel zapcore.Level, write
 			Name:       checkResult.Name,
 			Pass:       checkResult.Pass,
 			Confidence: checkResult.Confidence,
+			ErrorClass: checkResult.ErrorClass,
 		}
 		out.Checks = append(out.Checks, tmpResult)
 	}
-	if err := encoder.Encode(out); err != nil {
-		//nolint:wrapcheck
-		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("encoder.Encode: %v", err))
-	}
-	return nil
+	return out
 }
 
 // AsCSV outputs ScorecardResult in CSV format.
 func (r *ScorecardResult) AsCSV(showDetails bool, logLevel zapcore.Level, writer io.Writer) error {
+	return ResultsAsCSV([]ScorecardResult{*r}, showDetails, logLevel, writer)
+}
+
+// ResultsAsCSV outputs a list of ScorecardResult in CSV format, with a single
+// header row followed by one record per result. The header has the columns of
+// all the checks of the results, in the order they first appear, and results
+// without one of the checks have empty cells in its columns.
+func ResultsAsCSV(results []ScorecardResult, showDetails bool, logLevel zapcore.Level, writer io.Writer) error {
+	if len(results) == 0 {
+		return nil
+	}
+	checkNames := csvCheckNames(results)
 	w := csv.NewWriter(writer)
-	record := []string{r.Repo}
-	columns := []string{"Repository"}
-	// UPGRADEv2: remove nolint after uggrade.
-	//nolint
-	for _, checkResult := range r.Checks {
-		columns = append(columns, checkResult.Name+"_Pass", checkResult.Name+"_Confidence")
-		record = append(record, strconv.FormatBool(checkResult.Pass),
-			strconv.Itoa(checkResult.Confidence))
-		if showDetails {
-			columns = append(columns, checkResult.Name+"_Details")
-			record = append(record, checkResult.Details...)
+	fmt.Fprintf(writer, "%s\n", strings.Join(csvColumns(checkNames, showDetails), ","))
+	for i := range results {
+		if err := w.Write(results[i].csvRecord(checkNames, showDetails)); err != nil {
+			//nolint:wrapcheck
+			return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("csv.Write: %v", err))
 		}
 	}
-	fmt.Fprintf(writer, "%s\n", strings.Join(columns, ","))
-	if err := w.Write(record); err != nil {
-		//nolint:wrapcheck
-		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("csv.Write: %v", err))
-	}
 	w.Flush()
 	if err := w.Error(); err != nil {
 		//nolint:wrapcheck
@@ -100,6 +108,61 @@ func (r *ScorecardResult) AsCSV(showDetails bool, logLevel zapcore.Level, writer
 	return nil
 }
 
+// csvCheckNames returns the names of the checks of all results, in the order they
+// first appear.
+func csvCheckNames(results []ScorecardResult) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for i := range results {
+		// UPGRADEv2: remove nolint after uggrade.
+		//nolint
+		for _, checkResult := range results[i].Checks {
+			if !seen[checkResult.Name] {
+				seen[checkResult.Name] = true
+				names = append(names, checkResult.Name)
+			}
+		}
+	}
+	return names
+}
+
+func csvColumns(checkNames []string, showDetails bool) []string {
+	columns := []string{"Repository"}
+	for _, name := range checkNames {
+		columns = append(columns, name+"_Pass", name+"_Confidence")
+		if showDetails {
+			columns = append(columns, name+"_Details")
+		}
+	}
+	return columns
+}
+
+// csvRecord returns the cells of the result in the columns of checkNames. The details
+// of a check are joined by newlines, so that they fit in its column.
+func (r *ScorecardResult) csvRecord(checkNames []string, showDetails bool) []string {
+	checks := make(map[string]*checker.CheckResult, len(r.Checks))
+	for i := range r.Checks {
+		checks[r.Checks[i].Name] = &r.Checks[i]
+	}
+	record := []string{r.Repo}
+	for _, name := range checkNames {
+		checkResult, ok := checks[name]
+		switch {
+		case !ok && showDetails:
+			record = append(record, "", "", "")
+		case !ok:
+			record = append(record, "", "")
+		default:
+			record = append(record, strconv.FormatBool(checkResult.Pass),
+				strconv.Itoa(checkResult.Confidence))
+			if showDetails {
+				record = append(record, strings.Join(checkResult.Details, "\n"))
+			}
+		}
+	}
+	return record
+}
+
 // AsString returns ScorecardResult in string format.
 func (r *ScorecardResult) AsString(showDetails bool, logLevel zapcore.Level, writer io.Writer) error {
 	data := make([][]string, len(r.Checks))
@@ -0,0 +1,120 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/clients"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+func fakeChecks() checker.CheckNameToFnMap {
+	return checker.CheckNameToFnMap{
+		"Fake-Check": func(c *checker.CheckRequest) checker.CheckResult {
+			return checker.CreateMaxScoreResult("Fake-Check", "max")
+		},
+		"Other-Check": func(c *checker.CheckRequest) checker.CheckResult {
+			return checker.CreateMinScoreResult("Other-Check", "min")
+		},
+	}
+}
+
+func TestStreamScorecards(t *testing.T) {
+	t.Parallel()
+	repo := repos.RepoURL{Host: "github.com", Owner: "owner", Repo: "repo"}
+	started := make(map[string]bool)
+	var finished []string
+	err := StreamScorecards(context.Background(), repo, fakeChecks(), &fakeRepoClient{}, nil, nil, nil,
+		func(event CheckEvent) {
+			switch event.Type {
+			case CheckStarted:
+				if started[event.Check] {
+					t.Errorf("%s: started twice", event.Check)
+				}
+				started[event.Check] = true
+			case CheckFinished:
+				if !started[event.Check] {
+					t.Errorf("%s: finished before it started", event.Check)
+				}
+				if event.Result.Name != event.Check {
+					t.Errorf("%s: unexpected result %q", event.Check, event.Result.Name)
+				}
+				if event.Duration < 0 || event.Start.IsZero() {
+					t.Errorf("%s: unexpected timing %v %v", event.Check, event.Start, event.Duration)
+				}
+				finished = append(finished, event.Check)
+			}
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sort.Strings(finished)
+	if len(finished) != 2 || finished[0] != "Fake-Check" || finished[1] != "Other-Check" {
+		t.Errorf("unexpected finished checks: %v", finished)
+	}
+}
+
+func TestStreamScorecardsInitRepo(t *testing.T) {
+	t.Parallel()
+	repo := repos.RepoURL{Host: "github.com", Owner: "unreachable", Repo: "repo"}
+	err := StreamScorecards(context.Background(), repo, fakeChecks(), &fakeRepoClient{}, nil, nil, nil,
+		func(event CheckEvent) {
+			t.Errorf("unexpected event: %v %s", event.Type, event.Check)
+		})
+	var errRepoUnavailable *clients.ErrRepoUnavailable
+	if !errors.As(err, &errRepoUnavailable) {
+		t.Errorf("expected ErrRepoUnavailable, got: %v", err)
+	}
+}
+
+func TestRunScorecards(t *testing.T) {
+	t.Parallel()
+	repo := repos.RepoURL{Host: "github.com", Owner: "owner", Repo: "repo"}
+	result, err := RunScorecards(context.Background(), repo, fakeChecks(), &fakeRepoClient{}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Repo != repo.URL() {
+		t.Errorf("unexpected repo: %s", result.Repo)
+	}
+	if len(result.Checks) != 2 {
+		t.Errorf("expected 2 checks, got %d", len(result.Checks))
+	}
+}
+
+func TestRunScorecardsDefaultHost(t *testing.T) {
+	t.Parallel()
+	repo := repos.RepoURL{Owner: "owner", Repo: "repo"}
+	checks := checker.CheckNameToFnMap{
+		"Host-Check": func(c *checker.CheckRequest) checker.CheckResult {
+			if c.Host != "github.com" {
+				return checker.CreateMinScoreResult("Host-Check", c.Host)
+			}
+			return checker.CreateMaxScoreResult("Host-Check", c.Host)
+		},
+	}
+	result, err := RunScorecards(context.Background(), repo, checks, &fakeRepoClient{}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Checks) != 1 || result.Checks[0].Score != checker.MaxResultScore {
+		t.Errorf("expected the host to default to github.com, got: %v", result.Checks)
+	}
+}
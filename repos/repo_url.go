@@ -19,20 +19,49 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
+	"sync"
 
 	sce "github.com/ossf/scorecard/v2/errors"
 )
 
 var (
 	// ErrorUnsupportedHost indicates the repo's host is unsupported.
-	ErrorUnsupportedHost = errors.New("unsupported host")
+	ErrorUnsupportedHost = sce.ErrUnsupportedHost
 	// ErrorInvalidGithubURL indicates the repo's GitHub URL is not in the proper format.
 	ErrorInvalidGithubURL = errors.New("invalid GitHub repo URL")
 	// ErrorInvalidURL indicates the repo's full GitHub URL was not passed.
 	ErrorInvalidURL = errors.New("invalid repo flag")
 )
 
+// OriginalRepoMetadataPrefix prefixes the metadata entry recording the URL a renamed
+// or transferred repo was requested under.
+const OriginalRepoMetadataPrefix = "original-repo="
+
+// Matches scp-like git URLs, e.g. `git@github.com:owner/repo.git`.
+var scpLikeURL = regexp.MustCompile(`^[\w.-]+@([\w.-]+):/?(.+)$`)
+
+// githubHosts are the hosts accepted by ValidGitHubURL, i.e. github.com and any
+// GitHub Enterprise Server hosts registered with RegisterGitHubHost.
+var githubHosts = struct {
+	sync.RWMutex
+	hosts map[string]bool
+}{hosts: map[string]bool{"github.com": true}}
+
+// RegisterGitHubHost makes ValidGitHubURL accept repos on a GitHub Enterprise Server host.
+func RegisterGitHubHost(host string) {
+	githubHosts.Lock()
+	defer githubHosts.Unlock()
+	githubHosts.hosts[strings.ToLower(host)] = true
+}
+
+func isGitHubHost(host string) bool {
+	githubHosts.RLock()
+	defer githubHosts.RUnlock()
+	return githubHosts.hosts[strings.ToLower(host)]
+}
+
 //nolint:revive
 type RepoURL struct {
 	Host, Owner, Repo string
@@ -54,11 +83,20 @@ func (r *RepoURL) String() string {
 	return fmt.Sprintf("%s-%s-%s", r.Host, r.Owner, r.Repo)
 }
 
-// Set parses a URL string into RepoURL struct.
+// Set parses a URL string into RepoURL struct. Besides `host/owner/repo` with an optional
+// scheme, it accepts `ssh://`, `git://` and `git+https://` URLs, scp-like URLs such as
+// `git@github.com:owner/repo.git`, `.git` suffixes and deep links such as `/tree/main/dir`.
+// The result is in canonical form: the host, owner and repo are lowercased since
+// GitHub treats them case-insensitively.
 func (r *RepoURL) Set(s string) error {
-	// Allow skipping scheme for ease-of-use, default to https.
+	s = strings.TrimPrefix(strings.TrimSpace(s), "git+")
 	if !strings.Contains(s, "://") {
-		s = "https://" + s
+		if m := scpLikeURL.FindStringSubmatch(s); m != nil {
+			s = fmt.Sprintf("ssh://%s/%s", m[1], m[2])
+		} else {
+			// Allow skipping scheme for ease-of-use, default to https.
+			s = "https://" + s
+		}
 	}
 
 	u, e := url.Parse(s)
@@ -67,22 +105,41 @@ func (r *RepoURL) Set(s string) error {
 		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("url.Parse: %v", e))
 	}
 
-	const splitLen = 2
-	split := strings.SplitN(strings.Trim(u.Path, "/"), "/", splitLen)
-	if len(split) != splitLen {
+	host := u.Host
+	if u.Scheme != "http" && u.Scheme != "https" {
+		// The port of an SSH or git URL is not the port of the web host.
+		host = u.Hostname()
+	}
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+
+	const ownerRepoLen = 2
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+	if len(segments) < ownerRepoLen {
 		//nolint:wrapcheck
 		return sce.Create(ErrorInvalidURL, fmt.Sprintf("%v. Exepted full repository url", s))
 	}
 
-	r.Host, r.Owner, r.Repo = u.Host, split[0], split[1]
+	r.Host = host
+	r.Owner = strings.ToLower(segments[0])
+	r.Repo = strings.ToLower(strings.TrimSuffix(segments[1], ".git"))
 	return nil
 }
 
+// Rename moves RepoURL to the current owner and name of a renamed or transferred repo,
+// recording the URL it was requested under in its Metadata as `original-repo=<url>`.
+func (r *RepoURL) Rename(owner, repo string) {
+	owner, repo = strings.ToLower(owner), strings.ToLower(repo)
+	if owner == r.Owner && repo == r.Repo {
+		return
+	}
+	r.Metadata = append(r.Metadata, OriginalRepoMetadataPrefix+r.URL())
+	r.Owner, r.Repo = owner, repo
+}
+
 // ValidGitHubURL checks whether RepoURL represents a valid GitHub repo and returns errors otherwise.
+// Repos on registered GitHub Enterprise Server hosts are valid too.
 func (r *RepoURL) ValidGitHubURL() error {
-	switch r.Host {
-	case "github.com":
-	default:
+	if !isGitHubHost(r.Host) {
 		//nolint:wrapcheck
 		return sce.Create(ErrorUnsupportedHost, r.Host)
 	}
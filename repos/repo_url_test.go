@@ -95,3 +95,99 @@ func TestRepoURL_ValidGitHubUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoURL_ValidGitHubUrl_Enterprise(t *testing.T) {
+	t.Parallel()
+	r := &RepoURL{}
+	if err := r.Set("https://ghes.example.com/foo/bar"); err != nil {
+		t.Fatalf("RepoURL.Set() error = %v", err)
+	}
+	if err := r.ValidGitHubURL(); err == nil {
+		t.Errorf("RepoURL.ValidGitHubUrl() expected error for unregistered host")
+	}
+	RegisterGitHubHost("GHES.example.com")
+	if err := r.ValidGitHubURL(); err != nil {
+		t.Errorf("RepoURL.ValidGitHubUrl() error = %v", err)
+	}
+}
+
+func TestRepoURL_Set(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    RepoURL
+		wantErr bool
+	}{
+		{
+			name:  "scheme-less",
+			input: "github.com/ossf/scorecard",
+			want:  RepoURL{Host: "github.com", Owner: "ossf", Repo: "scorecard"},
+		},
+		{
+			name:  "scp-like",
+			input: "git@github.com:ossf/scorecard.git",
+			want:  RepoURL{Host: "github.com", Owner: "ossf", Repo: "scorecard"},
+		},
+		{
+			name:  "ssh with port",
+			input: "ssh://git@github.com:22/ossf/scorecard.git",
+			want:  RepoURL{Host: "github.com", Owner: "ossf", Repo: "scorecard"},
+		},
+		{
+			name:  "git+https",
+			input: "git+https://github.com/ossf/scorecard.git",
+			want:  RepoURL{Host: "github.com", Owner: "ossf", Repo: "scorecard"},
+		},
+		{
+			name:  "deep link",
+			input: "https://github.com/ossf/scorecard/tree/main/checks",
+			want:  RepoURL{Host: "github.com", Owner: "ossf", Repo: "scorecard"},
+		},
+		{
+			name:  "mixed case",
+			input: "https://www.GitHub.com/OSSF/Scorecard",
+			want:  RepoURL{Host: "github.com", Owner: "ossf", Repo: "scorecard"},
+		},
+		{
+			name:  "https with port",
+			input: "https://ghes.example.com:8443/foo/bar",
+			want:  RepoURL{Host: "ghes.example.com:8443", Owner: "foo", Repo: "bar"},
+		},
+		{
+			name:    "owner only",
+			input:   "https://github.com/ossf",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var r RepoURL
+			err := r.Set(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RepoURL.Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (r.Host != tt.want.Host || r.Owner != tt.want.Owner || r.Repo != tt.want.Repo) {
+				t.Errorf("RepoURL.Set() = %s, want %s", r.URL(), tt.want.URL())
+			}
+		})
+	}
+}
+
+func TestRepoURL_Rename(t *testing.T) {
+	t.Parallel()
+	r := RepoURL{Host: "github.com", Owner: "old", Repo: "name", Metadata: []string{"meta"}}
+	r.Rename("Old", "Name")
+	if len(r.Metadata) != 1 {
+		t.Errorf("unexpected metadata after case-only rename: %v", r.Metadata)
+	}
+	r.Rename("New", "Name")
+	if r.URL() != "github.com/new/name" {
+		t.Errorf("RepoURL.URL() = %s, want github.com/new/name", r.URL())
+	}
+	if len(r.Metadata) != 2 || r.Metadata[1] != "original-repo=github.com/old/name" {
+		t.Errorf("unexpected metadata after rename: %v", r.Metadata)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type cratesPackage struct {
+	Crate struct {
+		Repository string `json:"repository"`
+		Homepage   string `json:"homepage"`
+	} `json:"crate"`
+}
+
+type cratesResolver struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewCratesResolver returns a Resolver for crates.io packages using the registry at baseURL.
+func NewCratesResolver(client *http.Client, baseURL string) Resolver {
+	return &cratesResolver{client: client, baseURL: baseURL}
+}
+
+// Resolve implements Resolver.Resolve.
+func (r *cratesResolver) Resolve(ctx context.Context, packageName string) (string, error) {
+	v := cratesPackage{}
+	if err := getJSON(ctx, r.client, fmt.Sprintf("%s/api/v1/crates/%s", r.baseURL, packageName), &v); err != nil {
+		return "", fmt.Errorf("crate %s: %w", packageName, err)
+	}
+	if repo, ok := firstRepoURL(false, v.Crate.Repository); ok {
+		return repo, nil
+	}
+	if repo, ok := firstRepoURL(true, v.Crate.Homepage); ok {
+		return repo, nil
+	}
+	return "", fmt.Errorf("%w for crate: %s", ErrNoSourceRepo, packageName)
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// maxGoGetPageSize limits how much of a go-get page is read looking for the meta tag.
+const maxGoGetPageSize = 1 << 20
+
+var goImportMeta = regexp.MustCompile(`<meta\s+name="go-import"\s+content="([^"]+)"`)
+
+// goModuleInfo is the `@latest` response of the module proxy protocol.
+type goModuleInfo struct {
+	Origin struct {
+		URL string `json:"URL"`
+	} `json:"Origin"`
+}
+
+type goModulesResolver struct {
+	client  *http.Client
+	baseURL string
+	// goGetURL is prepended to module paths to fetch their go-get pages.
+	goGetURL string
+}
+
+// NewGoModulesResolver returns a Resolver for Go modules using the module proxy at baseURL.
+func NewGoModulesResolver(client *http.Client, baseURL string) Resolver {
+	return &goModulesResolver{client: client, baseURL: baseURL, goGetURL: "https://"}
+}
+
+// Resolve implements Resolver.Resolve.
+func (r *goModulesResolver) Resolve(ctx context.Context, modulePath string) (string, error) {
+	// Modules hosted on a code forge are named after their repository.
+	if repo, ok := firstRepoURL(true, modulePath); ok {
+		return repo, nil
+	}
+
+	// Otherwise ask the proxy, which records where it fetched the module from.
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", fmt.Errorf("%w: go module %s: %v", ErrPackageNotFound, modulePath, err)
+	}
+	v := goModuleInfo{}
+	if err := getJSON(ctx, r.client, fmt.Sprintf("%s/%s/@latest", r.baseURL, escaped), &v); err != nil {
+		return "", fmt.Errorf("go module %s: %w", modulePath, err)
+	}
+	if repo, ok := firstRepoURL(false, v.Origin.URL); ok {
+		return repo, nil
+	}
+
+	// Finally fall back to the go-import meta tag served for vanity import paths.
+	if repo, ok := r.resolveGoImport(ctx, modulePath); ok {
+		return repo, nil
+	}
+	return "", fmt.Errorf("%w for go module: %s", ErrNoSourceRepo, modulePath)
+}
+
+func (r *goModulesResolver) resolveGoImport(ctx context.Context, modulePath string) (string, bool) {
+	resp, err := get(ctx, r.client, fmt.Sprintf("%s%s?go-get=1", r.goGetURL, modulePath))
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+	page, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxGoGetPageSize))
+	if err != nil {
+		return "", false
+	}
+	for _, m := range goImportMeta.FindAllStringSubmatch(string(page), -1) {
+		// The content is `import-prefix vcs repo-root`.
+		const fieldsLen = 3
+		fields := strings.Fields(m[1])
+		if len(fields) != fieldsLen || !strings.HasPrefix(modulePath, fields[0]) || fields[1] == "mod" {
+			continue
+		}
+		if repo, ok := firstRepoURL(false, fields[2]); ok {
+			return repo, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxParentDepth bounds how many parent POMs are followed looking for an SCM URL.
+const maxParentDepth = 3
+
+var errInvalidMavenName = errors.New("maven packages must be named groupId:artifactId")
+
+type mavenMetadata struct {
+	Versioning struct {
+		Latest  string `xml:"latest"`
+		Release string `xml:"release"`
+	} `xml:"versioning"`
+}
+
+type mavenPOM struct {
+	URL string `xml:"url"`
+	SCM struct {
+		URL        string `xml:"url"`
+		Connection string `xml:"connection"`
+	} `xml:"scm"`
+	Parent struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+	} `xml:"parent"`
+}
+
+type mavenResolver struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewMavenResolver returns a Resolver for Maven packages, named `groupId:artifactId`,
+// using the repository at baseURL.
+func NewMavenResolver(client *http.Client, baseURL string) Resolver {
+	return &mavenResolver{client: client, baseURL: baseURL}
+}
+
+// Resolve implements Resolver.Resolve.
+func (r *mavenResolver) Resolve(ctx context.Context, packageName string) (string, error) {
+	const nameParts = 2
+	parts := strings.Split(packageName, ":")
+	if len(parts) != nameParts || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("%w: %s", errInvalidMavenName, packageName)
+	}
+	groupID, artifactID := parts[0], parts[1]
+
+	metadata := mavenMetadata{}
+	if err := getXML(ctx, r.client, r.artifactURL(groupID, artifactID, "maven-metadata.xml"), &metadata); err != nil {
+		return "", fmt.Errorf("maven package %s: %w", packageName, err)
+	}
+	version := metadata.Versioning.Release
+	if version == "" {
+		version = metadata.Versioning.Latest
+	}
+	if version == "" {
+		return "", fmt.Errorf("%w: maven package %s has no versions", ErrPackageNotFound, packageName)
+	}
+
+	// The SCM section is often only declared in a parent POM.
+	for depth := 0; depth <= maxParentDepth; depth++ {
+		pom := mavenPOM{}
+		pomFile := fmt.Sprintf("%s/%s-%s.pom", version, artifactID, version)
+		if err := getXML(ctx, r.client, r.artifactURL(groupID, artifactID, pomFile), &pom); err != nil {
+			return "", fmt.Errorf("maven package %s: %w", packageName, err)
+		}
+		if repo, ok := firstRepoURL(false, pom.SCM.URL, pom.SCM.Connection); ok {
+			return repo, nil
+		}
+		if repo, ok := firstRepoURL(true, pom.URL); ok {
+			return repo, nil
+		}
+		if pom.Parent.ArtifactID == "" {
+			break
+		}
+		groupID, artifactID, version = pom.Parent.GroupID, pom.Parent.ArtifactID, pom.Parent.Version
+	}
+	return "", fmt.Errorf("%w for maven package: %s", ErrNoSourceRepo, packageName)
+}
+
+func (r *mavenResolver) artifactURL(groupID, artifactID, file string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", r.baseURL, strings.ReplaceAll(groupID, ".", "/"), artifactID, file)
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidRepoURL indicates a URL could not be normalised to a repository URL.
+var ErrInvalidRepoURL = errors.New("invalid repo URL")
+
+var (
+	// Matches scp-like git URLs, e.g. `git@github.com:owner/repo.git`.
+	scpLikeURL = regexp.MustCompile(`^(?:[\w.-]+@)?([\w.-]+\.[\w]+):([^/].*)$`)
+
+	// Shorthands used in npm `repository` fields, e.g. `github:owner/repo`.
+	shorthandHosts = map[string]string{
+		"github":    "github.com",
+		"gitlab":    "gitlab.com",
+		"bitbucket": "bitbucket.org",
+	}
+
+	knownForges = map[string]bool{
+		"github.com":    true,
+		"gitlab.com":    true,
+		"bitbucket.org": true,
+	}
+)
+
+// NormalizeRepoURL converts the many ways registries record source repositories
+// into the form `https://host/owner/repo`. It handles `git+https://`, `git://`,
+// `ssh://` and scp-like URLs, `.git` suffixes, and deep links such as
+// `/tree/main/subdir`.
+func NormalizeRepoURL(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	// Maven uses `scm:git:<url>`, npm uses `git+<url>`.
+	s = strings.TrimPrefix(s, "scm:")
+	if strings.HasPrefix(s, "git:") && !strings.HasPrefix(s, "git://") {
+		s = strings.TrimPrefix(s, "git:")
+	}
+	s = strings.TrimPrefix(s, "git+")
+
+	if !strings.Contains(s, "://") {
+		if m := scpLikeURL.FindStringSubmatch(s); m != nil {
+			s = fmt.Sprintf("https://%s/%s", m[1], m[2])
+		} else if parts := strings.SplitN(s, ":", 2); len(parts) == 2 && shorthandHosts[parts[0]] != "" {
+			s = fmt.Sprintf("https://%s/%s", shorthandHosts[parts[0]], parts[1])
+		} else if strings.Count(s, "/") == 1 && !strings.Contains(s, ".") {
+			// npm treats a bare `owner/repo` as a GitHub repository.
+			s = "https://github.com/" + s
+		} else {
+			s = "https://" + s
+		}
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrInvalidRepoURL, raw, err)
+	}
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	if host == "" {
+		return "", fmt.Errorf("%w: %s", ErrInvalidRepoURL, raw)
+	}
+
+	const ownerRepoLen = 2
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+	if len(segments) < ownerRepoLen {
+		return "", fmt.Errorf("%w: %s", ErrInvalidRepoURL, raw)
+	}
+	owner := segments[0]
+	repo := strings.TrimSuffix(segments[1], ".git")
+	if owner == "" || repo == "" {
+		return "", fmt.Errorf("%w: %s", ErrInvalidRepoURL, raw)
+	}
+	return fmt.Sprintf("https://%s/%s/%s", host, owner, repo), nil
+}
+
+func isKnownForge(normalized string) bool {
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return false
+	}
+	return knownForges[u.Host]
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeRepoURL(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "Plain", input: "https://github.com/owner/repo", expected: "https://github.com/owner/repo"},
+		{name: "NoScheme", input: "github.com/owner/repo", expected: "https://github.com/owner/repo"},
+		{name: "GitPlusHTTPS", input: "git+https://github.com/owner/repo.git", expected: "https://github.com/owner/repo"},
+		{name: "GitProtocol", input: "git://github.com/owner/repo.git", expected: "https://github.com/owner/repo"},
+		{name: "GitPlusSSH", input: "git+ssh://git@github.com/owner/repo.git", expected: "https://github.com/owner/repo"},
+		{name: "ScpLike", input: "git@github.com:owner/repo.git", expected: "https://github.com/owner/repo"},
+		{name: "MavenSCM", input: "scm:git:git@github.com:owner/repo.git", expected: "https://github.com/owner/repo"},
+		{name: "MavenSCMHTTPS", input: "scm:git:https://github.com/owner/repo", expected: "https://github.com/owner/repo"},
+		{name: "Tree", input: "https://github.com/owner/repo/tree/main/subdir", expected: "https://github.com/owner/repo"},
+		{name: "Fragment", input: "https://github.com/owner/repo#readme", expected: "https://github.com/owner/repo"},
+		{name: "WWWAndCase", input: "https://WWW.GitHub.com/owner/repo/", expected: "https://github.com/owner/repo"},
+		{name: "Shorthand", input: "github:owner/repo", expected: "https://github.com/owner/repo"},
+		{name: "GitLabShorthand", input: "gitlab:owner/repo", expected: "https://gitlab.com/owner/repo"},
+		{name: "BareOwnerRepo", input: "owner/repo", expected: "https://github.com/owner/repo"},
+		{name: "Empty", input: "", wantErr: true},
+		{name: "HostOnly", input: "https://github.com/", wantErr: true},
+		{name: "OwnerOnly", input: "https://github.com/owner", wantErr: true},
+	}
+	for _, tt := range testcases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NormalizeRepoURL(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRepoURL) {
+					t.Errorf("expected ErrInvalidRepoURL, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
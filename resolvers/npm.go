@@ -0,0 +1,76 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// npmRepository is either a string or an object with a `url` field, see
+// https://docs.npmjs.com/cli/v7/configuring-npm/package-json#repository.
+type npmRepository struct {
+	URL string
+}
+
+func (r *npmRepository) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &r.URL); err == nil {
+		return nil
+	}
+	var v struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("error parsing npm repository: %w", err)
+	}
+	r.URL = v.URL
+	return nil
+}
+
+type npmPackage struct {
+	Repository npmRepository `json:"repository"`
+	Homepage   string        `json:"homepage"`
+}
+
+type npmResolver struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewNPMResolver returns a Resolver for npm packages using the registry at baseURL.
+func NewNPMResolver(client *http.Client, baseURL string) Resolver {
+	return &npmResolver{client: client, baseURL: baseURL}
+}
+
+// Resolve implements Resolver.Resolve.
+func (r *npmResolver) Resolve(ctx context.Context, packageName string) (string, error) {
+	// Scoped packages keep their `@` but need the `/` escaped.
+	name := strings.Replace(url.PathEscape(packageName), "%40", "@", 1)
+	v := npmPackage{}
+	if err := getJSON(ctx, r.client, fmt.Sprintf("%s/%s", r.baseURL, name), &v); err != nil {
+		return "", fmt.Errorf("npm package %s: %w", packageName, err)
+	}
+	if repo, ok := firstRepoURL(false, v.Repository.URL); ok {
+		return repo, nil
+	}
+	if repo, ok := firstRepoURL(true, v.Homepage); ok {
+		return repo, nil
+	}
+	return "", fmt.Errorf("%w for npm package: %s", ErrNoSourceRepo, packageName)
+}
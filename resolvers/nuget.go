@@ -0,0 +1,72 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type nugetVersions struct {
+	Versions []string `json:"versions"`
+}
+
+type nugetSpec struct {
+	Metadata struct {
+		Repository struct {
+			URL string `xml:"url,attr"`
+		} `xml:"repository"`
+		ProjectURL string `xml:"projectUrl"`
+	} `xml:"metadata"`
+}
+
+type nugetResolver struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewNuGetResolver returns a Resolver for NuGet packages using the package
+// content (flat container) API at baseURL.
+func NewNuGetResolver(client *http.Client, baseURL string) Resolver {
+	return &nugetResolver{client: client, baseURL: baseURL}
+}
+
+// Resolve implements Resolver.Resolve.
+func (r *nugetResolver) Resolve(ctx context.Context, packageName string) (string, error) {
+	id := strings.ToLower(packageName)
+	versions := nugetVersions{}
+	if err := getJSON(ctx, r.client, fmt.Sprintf("%s/%s/index.json", r.baseURL, id), &versions); err != nil {
+		return "", fmt.Errorf("nuget package %s: %w", packageName, err)
+	}
+	if len(versions.Versions) == 0 {
+		return "", fmt.Errorf("%w: nuget package %s has no versions", ErrPackageNotFound, packageName)
+	}
+	// Versions are listed oldest first.
+	version := strings.ToLower(versions.Versions[len(versions.Versions)-1])
+
+	spec := nugetSpec{}
+	if err := getXML(ctx, r.client, fmt.Sprintf("%s/%s/%s/%s.nuspec", r.baseURL, id, version, id), &spec); err != nil {
+		return "", fmt.Errorf("nuget package %s: %w", packageName, err)
+	}
+	if repo, ok := firstRepoURL(false, spec.Metadata.Repository.URL); ok {
+		return repo, nil
+	}
+	if repo, ok := firstRepoURL(true, spec.Metadata.ProjectURL); ok {
+		return repo, nil
+	}
+	return "", fmt.Errorf("%w for nuget package: %s", ErrNoSourceRepo, packageName)
+}
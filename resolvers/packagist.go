@@ -0,0 +1,59 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type packagistPackage struct {
+	Packages map[string][]struct {
+		Source struct {
+			URL string `json:"url"`
+		} `json:"source"`
+		Homepage string `json:"homepage"`
+	} `json:"packages"`
+}
+
+type packagistResolver struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewPackagistResolver returns a Resolver for Packagist packages, named `vendor/package`,
+// using the registry at baseURL.
+func NewPackagistResolver(client *http.Client, baseURL string) Resolver {
+	return &packagistResolver{client: client, baseURL: baseURL}
+}
+
+// Resolve implements Resolver.Resolve.
+func (r *packagistResolver) Resolve(ctx context.Context, packageName string) (string, error) {
+	v := packagistPackage{}
+	if err := getJSON(ctx, r.client, fmt.Sprintf("%s/p2/%s.json", r.baseURL, packageName), &v); err != nil {
+		return "", fmt.Errorf("packagist package %s: %w", packageName, err)
+	}
+	// Versions are listed newest first.
+	for _, version := range v.Packages[packageName] {
+		if repo, ok := firstRepoURL(false, version.Source.URL); ok {
+			return repo, nil
+		}
+		if repo, ok := firstRepoURL(true, version.Homepage); ok {
+			return repo, nil
+		}
+	}
+	return "", fmt.Errorf("%w for packagist package: %s", ErrNoSourceRepo, packageName)
+}
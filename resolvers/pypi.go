@@ -0,0 +1,65 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// pypiSourceKeys are the `project_urls` keys which commonly point to the source
+// repository, in order of preference.
+var pypiSourceKeys = []string{"source", "source code", "code", "repository", "github", "homepage"}
+
+type pypiPackage struct {
+	Info struct {
+		ProjectUrls map[string]string `json:"project_urls"`
+		HomePage    string            `json:"home_page"`
+	} `json:"info"`
+}
+
+type pypiResolver struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewPyPIResolver returns a Resolver for PyPI packages using the registry at baseURL.
+func NewPyPIResolver(client *http.Client, baseURL string) Resolver {
+	return &pypiResolver{client: client, baseURL: baseURL}
+}
+
+// Resolve implements Resolver.Resolve.
+func (r *pypiResolver) Resolve(ctx context.Context, packageName string) (string, error) {
+	v := pypiPackage{}
+	if err := getJSON(ctx, r.client, fmt.Sprintf("%s/pypi/%s/json", r.baseURL, packageName), &v); err != nil {
+		return "", fmt.Errorf("pypi package %s: %w", packageName, err)
+	}
+	projectURLs := make(map[string]string, len(v.Info.ProjectUrls))
+	for key, value := range v.Info.ProjectUrls {
+		projectURLs[strings.ToLower(key)] = value
+	}
+	candidates := make([]string, 0, len(pypiSourceKeys)+1)
+	for _, key := range pypiSourceKeys {
+		candidates = append(candidates, projectURLs[key])
+	}
+	candidates = append(candidates, v.Info.HomePage)
+	// PyPI URLs are free-form, so only trust those pointing to a code forge.
+	if repo, ok := firstRepoURL(true, candidates...); ok {
+		return repo, nil
+	}
+	return "", fmt.Errorf("%w for pypi package: %s", ErrNoSourceRepo, packageName)
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resolvers resolves packages of various ecosystems to their source repository.
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+// Supported package ecosystems.
+const (
+	NPM       = "npm"
+	PyPI      = "pypi"
+	RubyGems  = "rubygems"
+	Crates    = "crates"
+	GoModules = "go"
+	Maven     = "maven"
+	NuGet     = "nuget"
+	Packagist = "packagist"
+)
+
+const userAgent = "ossf-scorecard"
+
+var (
+	// ErrPackageNotFound indicates the package does not exist in the registry.
+	ErrPackageNotFound = errors.New("package not found")
+	// ErrNoSourceRepo indicates the package exists but has no source repository.
+	ErrNoSourceRepo = errors.New("could not find source repo")
+	// ErrUnsupportedEcosystem indicates there is no Resolver for the ecosystem.
+	ErrUnsupportedEcosystem = errors.New("unsupported ecosystem")
+
+	errUnexpectedStatus = errors.New("unexpected HTTP status")
+)
+
+// Resolver resolves a package name to the URL of its source repository.
+type Resolver interface {
+	// Resolve returns the normalised repository URL for the package,
+	// e.g. `https://github.com/owner/repo`.
+	Resolve(ctx context.Context, packageName string) (string, error)
+}
+
+type constructor func(client *http.Client, baseURL string) Resolver
+
+// registry maps each ecosystem to its Resolver constructor and public registry URL.
+var registry = map[string]struct {
+	new     constructor
+	baseURL string
+}{
+	NPM:       {NewNPMResolver, "https://registry.npmjs.org"},
+	PyPI:      {NewPyPIResolver, "https://pypi.org"},
+	RubyGems:  {NewRubyGemsResolver, "https://rubygems.org"},
+	Crates:    {NewCratesResolver, "https://crates.io"},
+	GoModules: {NewGoModulesResolver, "https://proxy.golang.org"},
+	Maven:     {NewMavenResolver, "https://repo1.maven.org/maven2"},
+	NuGet:     {NewNuGetResolver, "https://api.nuget.org/v3-flatcontainer"},
+	Packagist: {NewPackagistResolver, "https://repo.packagist.org"},
+}
+
+// Ecosystems returns the names of all supported ecosystems, sorted.
+func Ecosystems() []string {
+	ret := make([]string, 0, len(registry))
+	for ecosystem := range registry {
+		ret = append(ret, ecosystem)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Get returns the Resolver for the ecosystem, using its public registry.
+func Get(ecosystem string, client *http.Client) (Resolver, error) {
+	entry, ok := registry[ecosystem]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEcosystem, ecosystem)
+	}
+	return entry.new(client, entry.baseURL), nil
+}
+
+// get issues a GET request for url and returns the response if its status is 200 OK.
+// The caller must close the response body.
+func get(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("http.NewRequestWithContext: %v", err))
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("client.Do: %v", err))
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound, http.StatusGone:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrPackageNotFound, url)
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s: %s", errUnexpectedStatus, url, resp.Status)
+	}
+}
+
+// getJSON fetches url and decodes the JSON response into v.
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	resp, err := get(ctx, client, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("json.Decode: %s: %v", url, err))
+	}
+	return nil
+}
+
+// getXML fetches url and decodes the XML response into v.
+func getXML(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	resp, err := get(ctx, client, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := xml.NewDecoder(resp.Body).Decode(v); err != nil {
+		//nolint:wrapcheck
+		return sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("xml.Decode: %s: %v", url, err))
+	}
+	return nil
+}
+
+// firstRepoURL returns the first candidate which normalises to a repository URL.
+// If forgeOnly is set, only candidates hosted on a known code forge are accepted,
+// which is needed for free-form fields like homepages.
+func firstRepoURL(forgeOnly bool, candidates ...string) (string, bool) {
+	for _, candidate := range candidates {
+		normalized, err := NormalizeRepoURL(candidate)
+		if err != nil {
+			continue
+		}
+		if forgeOnly && !isKnownForge(normalized) {
+			continue
+		}
+		return normalized, true
+	}
+	return "", false
+}
@@ -0,0 +1,209 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// registryResponses maps request paths to the body served by the test registry.
+var registryResponses = map[string]string{
+	// npm.
+	"/left-pad":        `{"repository": {"type": "git", "url": "git+https://github.com/stevemao/left-pad.git"}}`,
+	"/@angular%2Fcore": `{"repository": "github:angular/angular"}`,
+	"/no-repo":         `{"homepage": "https://example.com"}`,
+	// PyPI.
+	"/pypi/requests/json": `{"info": {"project_urls": {"Documentation": "https://requests.readthedocs.io",
+		"Source": "https://github.com/psf/requests"}, "home_page": "https://requests.readthedocs.io"}}`,
+	"/pypi/homepage-only/json": `{"info": {"project_urls": null, "home_page": "https://gitlab.com/owner/repo"}}`,
+	// RubyGems.
+	"/api/v1/gems/rails.json": `{"source_code_uri": "https://github.com/rails/rails/tree/v6.1.4"}`,
+	// crates.io.
+	"/api/v1/crates/serde": `{"crate": {"repository": "https://github.com/serde-rs/serde"}}`,
+	// Go module proxy.
+	"/go.uber.org/zap/@latest": `{"Version": "v1.18.1", "Origin": {"VCS": "git",
+		"URL": "https://github.com/uber-go/zap"}}`,
+	"/vanity.example/mod/@latest": `{"Version": "v1.0.0"}`,
+	"/vanity.example/mod": `<html><head>
+		<meta name="go-import" content="vanity.example/mod mod https://vanity.example/proxy">
+		<meta name="go-import" content="vanity.example/mod git https://gitlab.com/owner/mod.git">
+		</head></html>`,
+	// Maven Central.
+	"/com/example/child/maven-metadata.xml": `<metadata><versioning><release>1.0</release></versioning></metadata>`,
+	"/com/example/child/1.0/child-1.0.pom": `<project><parent><groupId>com.example</groupId>
+		<artifactId>parent</artifactId><version>2.0</version></parent></project>`,
+	"/com/example/parent/2.0/parent-2.0.pom": `<project><scm>
+		<connection>scm:git:git@github.com:example/parent.git</connection></scm></project>`,
+	// NuGet.
+	"/newtonsoft.json/index.json": `{"versions": ["12.0.3", "13.0.1"]}`,
+	"/newtonsoft.json/13.0.1/newtonsoft.json.nuspec": `<?xml version="1.0"?>
+		<package xmlns="http://schemas.microsoft.com/packaging/2013/05/nuspec.xsd"><metadata>
+		<repository type="git" url="https://github.com/JamesNK/Newtonsoft.Json.git" /></metadata></package>`,
+	// Packagist.
+	"/p2/monolog/monolog.json": `{"packages": {"monolog/monolog": [
+		{"source": {"url": "https://github.com/Seldaek/monolog.git"}}]}}`,
+}
+
+func TestResolvers(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := registryResponses[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	testcases := []struct {
+		expectedErr error
+		newResolver constructor
+		name        string
+		packageName string
+		expected    string
+	}{
+		{
+			name:        "NPM",
+			newResolver: NewNPMResolver,
+			packageName: "left-pad",
+			expected:    "https://github.com/stevemao/left-pad",
+		},
+		{
+			name:        "NPMScoped",
+			newResolver: NewNPMResolver,
+			packageName: "@angular/core",
+			expected:    "https://github.com/angular/angular",
+		},
+		{
+			name:        "NPMNoRepo",
+			newResolver: NewNPMResolver,
+			packageName: "no-repo",
+			expectedErr: ErrNoSourceRepo,
+		},
+		{
+			name:        "NPMNotFound",
+			newResolver: NewNPMResolver,
+			packageName: "does-not-exist",
+			expectedErr: ErrPackageNotFound,
+		},
+		{
+			name:        "PyPI",
+			newResolver: NewPyPIResolver,
+			packageName: "requests",
+			expected:    "https://github.com/psf/requests",
+		},
+		{
+			name:        "PyPIHomepage",
+			newResolver: NewPyPIResolver,
+			packageName: "homepage-only",
+			expected:    "https://gitlab.com/owner/repo",
+		},
+		{
+			name:        "RubyGems",
+			newResolver: NewRubyGemsResolver,
+			packageName: "rails",
+			expected:    "https://github.com/rails/rails",
+		},
+		{
+			name:        "Crates",
+			newResolver: NewCratesResolver,
+			packageName: "serde",
+			expected:    "https://github.com/serde-rs/serde",
+		},
+		{
+			name:        "GoModulesForge",
+			newResolver: NewGoModulesResolver,
+			packageName: "github.com/owner/repo/v2/pkg",
+			expected:    "https://github.com/owner/repo",
+		},
+		{
+			name:        "GoModulesProxy",
+			newResolver: NewGoModulesResolver,
+			packageName: "go.uber.org/zap",
+			expected:    "https://github.com/uber-go/zap",
+		},
+		{
+			name: "GoModulesGoImport",
+			// The go-get page is also served by the test registry.
+			newResolver: func(client *http.Client, baseURL string) Resolver {
+				return &goModulesResolver{client: client, baseURL: baseURL, goGetURL: baseURL + "/"}
+			},
+			packageName: "vanity.example/mod",
+			expected:    "https://gitlab.com/owner/mod",
+		},
+		{
+			name:        "MavenParent",
+			newResolver: NewMavenResolver,
+			packageName: "com.example:child",
+			expected:    "https://github.com/example/parent",
+		},
+		{
+			name:        "MavenNotFound",
+			newResolver: NewMavenResolver,
+			packageName: "com.example:missing",
+			expectedErr: ErrPackageNotFound,
+		},
+		{
+			name:        "NuGet",
+			newResolver: NewNuGetResolver,
+			packageName: "Newtonsoft.Json",
+			expected:    "https://github.com/JamesNK/Newtonsoft.Json",
+		},
+		{
+			name:        "Packagist",
+			newResolver: NewPackagistResolver,
+			packageName: "monolog/monolog",
+			expected:    "https://github.com/Seldaek/monolog",
+		},
+	}
+	for _, tt := range testcases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resolver := tt.newResolver(server.Client(), server.URL)
+			got, err := resolver.Resolve(context.Background(), tt.packageName)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error %v, got: %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+	if _, err := Get("unknown", http.DefaultClient); !errors.Is(err, ErrUnsupportedEcosystem) {
+		t.Errorf("expected ErrUnsupportedEcosystem, got: %v", err)
+	}
+	for _, ecosystem := range Ecosystems() {
+		if _, err := Get(ecosystem, http.DefaultClient); err != nil {
+			t.Errorf("unexpected error for %s: %v", ecosystem, err)
+		}
+	}
+}
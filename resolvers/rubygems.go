@@ -0,0 +1,51 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type rubyGemsPackage struct {
+	SourceCodeURI string `json:"source_code_uri"`
+	HomepageURI   string `json:"homepage_uri"`
+}
+
+type rubyGemsResolver struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewRubyGemsResolver returns a Resolver for RubyGems packages using the registry at baseURL.
+func NewRubyGemsResolver(client *http.Client, baseURL string) Resolver {
+	return &rubyGemsResolver{client: client, baseURL: baseURL}
+}
+
+// Resolve implements Resolver.Resolve.
+func (r *rubyGemsResolver) Resolve(ctx context.Context, packageName string) (string, error) {
+	v := rubyGemsPackage{}
+	if err := getJSON(ctx, r.client, fmt.Sprintf("%s/api/v1/gems/%s.json", r.baseURL, packageName), &v); err != nil {
+		return "", fmt.Errorf("ruby gem %s: %w", packageName, err)
+	}
+	if repo, ok := firstRepoURL(false, v.SourceCodeURI); ok {
+		return repo, nil
+	}
+	if repo, ok := firstRepoURL(true, v.HomepageURI); ok {
+		return repo, nil
+	}
+	return "", fmt.Errorf("%w for ruby gem: %s", ErrNoSourceRepo, packageName)
+}
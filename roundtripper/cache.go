@@ -0,0 +1,102 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"encoding/binary"
+	"net/http"
+	"time"
+
+	"github.com/naveensrinivasan/httpcache"
+	"github.com/naveensrinivasan/httpcache/diskcache"
+)
+
+// xRevalidated is set on cached responses which GitHub confirmed to be unchanged
+// with a 304 Not Modified. httpcache copies it from the 304 onto the cached response.
+const xRevalidated = "X-Scorecard-Revalidated"
+
+const timestampLen = 8
+
+// MakeCachingTransport returns a RoundTripper which caches responses in cacheDir.
+// Cached responses are revalidated with their ETag using `If-None-Match`, and the
+// resulting 304 Not Modified responses don't count against GitHub's rate limit.
+// Entries which have not been stored or revalidated for longer than ttl are dropped.
+func MakeCachingTransport(innerTransport http.RoundTripper, cacheDir string, ttl time.Duration) http.RoundTripper {
+	transport := httpcache.NewTransport(&expiringCache{
+		cache: diskcache.New(cacheDir),
+		ttl:   ttl,
+	})
+	transport.Transport = &revalidationTransport{
+		innerTransport: innerTransport,
+	}
+	return transport
+}
+
+// revalidationTransport marks 304 Not Modified responses with xRevalidated.
+type revalidationTransport struct {
+	innerTransport http.RoundTripper
+}
+
+func (rt *revalidationTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := rt.innerTransport.RoundTrip(r)
+	if err != nil {
+		// nolint: wrapcheck
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Header.Set(xRevalidated, "1")
+	}
+	return resp, nil
+}
+
+// expiringCache is a httpcache.Cache which prefixes entries with the time they were
+// stored, and drops entries older than ttl.
+type expiringCache struct {
+	cache httpcache.Cache
+	ttl   time.Duration
+}
+
+func (c *expiringCache) Get(key string) ([]byte, bool) {
+	entry, ok := c.cache.Get(key)
+	if !ok || len(entry) < timestampLen {
+		return nil, false
+	}
+	stored := time.Unix(int64(binary.BigEndian.Uint64(entry[:timestampLen])), 0)
+	if c.ttl > 0 && time.Since(stored) > c.ttl {
+		c.cache.Delete(key)
+		return nil, false
+	}
+	return entry[timestampLen:], true
+}
+
+func (c *expiringCache) Set(key string, resp []byte) {
+	c.cache.Set(key, append(timestamp(time.Now()), resp...))
+}
+
+func (c *expiringCache) Delete(key string) {
+	c.cache.Delete(key)
+}
+
+func timestamp(t time.Time) []byte {
+	ret := make([]byte, timestampLen)
+	binary.BigEndian.PutUint64(ret, uint64(t.Unix()))
+	return ret
+}
+
+// isFreshCacheHit returns true if the response was served from the cache without
+// contacting GitHub, in which case its rate limit headers are stale.
+func isFreshCacheHit(resp *http.Response) bool {
+	return resp.Header.Get(httpcache.XFromCache) != "" && resp.Header.Get(xRevalidated) == ""
+}
@@ -0,0 +1,117 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/naveensrinivasan/httpcache"
+)
+
+const etag = `"v1"`
+
+func TestCachingTransport(t *testing.T) {
+	t.Parallel()
+	var requests, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "private, max-age=0")
+		if r.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(w, "content")
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{
+		Transport: MakeCachingTransport(http.DefaultTransport, t.TempDir(), time.Hour),
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("client.Get: %v", err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("ioutil.ReadAll: %v", err)
+		}
+		if string(body) != "content" {
+			t.Errorf("request %d: expected body %q, got %q", i, "content", body)
+		}
+		revalidated := resp.Header.Get(httpcache.XFromCache) != "" && resp.Header.Get(xRevalidated) != ""
+		if revalidated != (i == 1) {
+			t.Errorf("request %d: unexpected revalidated: %v", i, revalidated)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("expected 2 requests with 1 Not Modified, got %d and %d", requests, notModified)
+	}
+}
+
+func TestExpiringCache(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name     string
+		ttl      time.Duration
+		stored   time.Time
+		expected bool
+	}{
+		{
+			name:     "Fresh",
+			ttl:      time.Hour,
+			stored:   time.Now().Add(-time.Minute),
+			expected: true,
+		},
+		{
+			name:     "Expired",
+			ttl:      time.Hour,
+			stored:   time.Now().Add(-2 * time.Hour),
+			expected: false,
+		},
+		{
+			name:     "NoTTL",
+			ttl:      0,
+			stored:   time.Now().Add(-24 * time.Hour),
+			expected: true,
+		},
+	}
+	for _, tt := range testcases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			memory := httpcache.NewMemoryCache()
+			memory.Set("key", append(timestamp(tt.stored), "value"...))
+			cache := &expiringCache{cache: memory, ttl: tt.ttl}
+
+			value, ok := cache.Get("key")
+			if ok != tt.expected {
+				t.Fatalf("expected hit %v, got %v", tt.expected, ok)
+			}
+			if ok && string(value) != "value" {
+				t.Errorf("expected value %q, got %q", "value", value)
+			}
+		})
+	}
+}
@@ -43,7 +43,7 @@ type censusTransport struct {
 
 // Roundtrip handles context update and measurement recording.
 func (ct *censusTransport) RoundTrip(r *http.Request) (*http.Response, error) {
-	ctx, err := tag.New(r.Context(), tag.Upsert(stats.RequestTag, "requested"))
+	ctx, err := tag.New(r.Context(), tag.Upsert(stats.RequestTag, stats.RequestTagRequested))
 	if err != nil {
 		//nolint:wrapcheck
 		return nil, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("tag.New: %v", err))
@@ -56,7 +56,12 @@ func (ct *censusTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 		return nil, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("innerTransport.RoundTrip: %v", err))
 	}
 	if resp.Header.Get(httpcache.XFromCache) != "" {
-		ctx, err = tag.New(ctx, tag.Upsert(stats.RequestTag, httpcache.XFromCache))
+		// Fresh hits are served from the cache, revalidated ones cost a free 304 from GitHub.
+		requestTag := stats.RequestTagCacheHit
+		if resp.Header.Get(xRevalidated) != "" {
+			requestTag = stats.RequestTagCacheRevalidated
+		}
+		ctx, err = tag.New(ctx, tag.Upsert(stats.RequestTag, requestTag))
 		if err != nil {
 			//nolint:wrapcheck
 			return nil, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("tag.New: %v", err))
@@ -15,14 +15,35 @@
 package roundtripper
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io/ioutil"
+	"math/rand"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	opencensusstats "go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 	"go.uber.org/zap"
 
 	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/stats"
+)
+
+const (
+	// maxRetries bounds the number of times a request is retried.
+	maxRetries = 5
+	// baseBackoff is the backoff before the first retry of a server error, doubled on each retry.
+	baseBackoff = time.Second
+	// secondaryRateLimitWait is how long to wait on a secondary rate limit without `Retry-After`,
+	// as recommended by GitHub.
+	secondaryRateLimitWait = time.Minute
+	// maxRetryAfter bounds the `Retry-After` of secondary rate limits which is waited for.
+	// Requests asked to wait longer fail with ErrRateLimited.
+	maxRetryAfter = 5 * time.Minute
 )
 
 // MakeRateLimitedTransport returns a RoundTripper which rate limits GitHub requests.
@@ -30,42 +51,146 @@ func MakeRateLimitedTransport(innerTransport http.RoundTripper, logger *zap.Suga
 	return &rateLimitTransport{
 		logger:         logger,
 		innerTransport: innerTransport,
+		backoff:        jitteredBackoff,
 	}
 }
 
 // rateLimitTransport is a rate-limit aware http.Transport for Github.
+// It waits out primary and secondary rate limits and retries server errors with
+// jittered exponential backoff, at most maxRetries times.
 type rateLimitTransport struct {
 	logger         *zap.SugaredLogger
 	innerTransport http.RoundTripper
+	// backoff returns how long to wait before the given retry of a server error.
+	backoff func(retry int) time.Duration
 }
 
 // Roundtrip handles caching and ratelimiting of responses from GitHub.
 func (gh *rateLimitTransport) RoundTrip(r *http.Request) (*http.Response, error) {
-	resp, err := gh.innerTransport.RoundTrip(r)
-	if err != nil {
-		//nolint:wrapcheck
-		return nil, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("innerTransport.RoundTrip: %v", err))
+	for retry := 0; ; retry++ {
+		req := r
+		if retry > 0 && r.Body != nil {
+			body, err := r.GetBody()
+			if err != nil {
+				//nolint:wrapcheck
+				return nil, sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("GetBody: %v", err))
+			}
+			req = r.Clone(r.Context())
+			req.Body = body
+		}
+		resp, err := gh.innerTransport.RoundTrip(req)
+		if err != nil {
+			// Timeouts are reported as ErrTimeout.
+			//nolint:wrapcheck
+			return nil, sce.CreateFrom(err, "innerTransport.RoundTrip")
+		}
+		if retry >= maxRetries {
+			return resp, nil
+		}
+
+		duration, reason := gh.retryAfter(resp, retry)
+		if reason == "" {
+			return resp, nil
+		}
+		// A huge or bogus `Retry-After` would block the request indefinitely.
+		if reason == stats.RetryReasonSecondaryRateLimit && duration > maxRetryAfter {
+			resp.Body.Close()
+			//nolint:wrapcheck
+			return nil, sce.Create(sce.ErrRateLimited, fmt.Sprintf("%s for %s: Retry-After of %ss exceeds %s",
+				reason, r.URL.Redacted(), resp.Header.Get("Retry-After"), maxRetryAfter))
+		}
+		// Requests whose body cannot be sent again get the response as is.
+		if r.Body != nil && r.GetBody == nil {
+			gh.logger.Warnf("%s for %s, which cannot be retried without GetBody", reason, r.URL.Redacted())
+			return resp, nil
+		}
+		resp.Body.Close()
+		gh.logger.Warnf("%s for %s. Waiting %s to retry...", reason, r.URL.Redacted(), duration)
+		if err := wait(r.Context(), duration, reason); err != nil {
+			return nil, err
+		}
 	}
-	rateLimit := resp.Header.Get("X-RateLimit-Remaining")
-	remaining, err := strconv.Atoi(rateLimit)
-	if err != nil {
-		return resp, nil
+}
+
+// retryAfter returns how long to wait before retrying the request, and the reason
+// for retrying, which is empty if the request should not be retried.
+func (gh *rateLimitTransport) retryAfter(resp *http.Response, retry int) (time.Duration, string) {
+	if isFreshCacheHit(resp) {
+		return 0, ""
+	}
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusTooManyRequests:
+	default:
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return gh.backoff(retry), stats.RetryReasonServerError
+		}
+		return 0, ""
 	}
 
-	if remaining <= 0 {
-		reset, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Reset"))
+	// Secondary rate limits come with `Retry-After`, which takes precedence.
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		// Larger values are only compared with maxRetryAfter, and could overflow.
+		maxSeconds := int(maxRetryAfter / time.Second)
+		if seconds > maxSeconds {
+			seconds = maxSeconds + 1
+		}
+		if seconds < 0 {
+			seconds = 0
+		}
+		return time.Duration(seconds) * time.Second, stats.RetryReasonSecondaryRateLimit
+	}
+	// Only wait if the request was rejected because the token transport ran out of tokens,
+	// which is when X-RateLimit-Remaining is 0.
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
 		if err != nil {
-			return resp, nil
+			return 0, ""
 		}
+		return time.Until(time.Unix(reset, 0)), stats.RetryReasonPrimaryRateLimit
+	}
+	if isSecondaryRateLimit(resp) {
+		return secondaryRateLimitWait, stats.RetryReasonSecondaryRateLimit
+	}
+	return 0, ""
+}
+
+// isSecondaryRateLimit checks the body of a 403 response for GitHub's secondary rate limit
+// message, which older GitHub versions call abuse detection. The body is restored for the caller.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	message := strings.ToLower(string(body))
+	return strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse")
+}
 
-		duration := time.Until(time.Unix(int64(reset), 0))
-		gh.logger.Warnf("Rate limit exceeded. Waiting %s to retry...", duration)
+// wait blocks for duration, or until ctx is done, and records the time spent waiting.
+func wait(ctx context.Context, duration time.Duration, reason string) error {
+	start := time.Now()
+	defer func() {
+		ctx, err := tag.New(ctx, tag.Upsert(stats.RetryReason, reason))
+		if err != nil {
+			return
+		}
+		opencensusstats.Record(ctx, stats.HTTPRetryWaitInMs.M(time.Since(start).Milliseconds()))
+	}()
 
-		// Retry
-		time.Sleep(duration)
-		gh.logger.Warnf("Rate limit exceeded. Retrying...")
-		return gh.RoundTrip(r)
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting to retry: %w", ctx.Err())
+	case <-timer.C:
+		return nil
 	}
+}
 
-	return resp, nil
+// jitteredBackoff returns an exponential backoff with up to 100% random jitter.
+func jitteredBackoff(retry int) time.Duration {
+	backoff := baseBackoff << retry
+	// nolint: gosec // Jitter does not need a secure random number generator.
+	return backoff + time.Duration(rand.Int63n(int64(backoff)))
 }
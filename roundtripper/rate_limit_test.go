@@ -0,0 +1,232 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+// respondWith returns a handler which writes the nth response for the nth request,
+// repeating the last one.
+func respondWith(requests *int32, responses ...func(w http.ResponseWriter)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(requests, 1)) - 1
+		if n >= len(responses) {
+			n = len(responses) - 1
+		}
+		responses[n](w)
+	})
+}
+
+func status(code int, headers ...string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(code)
+		fmt.Fprint(w, http.StatusText(code))
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name             string
+		responses        []func(w http.ResponseWriter)
+		expectedStatus   int
+		expectedRequests int32
+		withoutGetBody   bool
+	}{
+		{
+			name:             "Success",
+			responses:        []func(w http.ResponseWriter){status(http.StatusOK)},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 1,
+		},
+		{
+			name: "ServerErrorsAreRetried",
+			responses: []func(w http.ResponseWriter){
+				status(http.StatusBadGateway),
+				status(http.StatusServiceUnavailable),
+				status(http.StatusOK),
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 3,
+		},
+		{
+			name:             "RetriesAreBounded",
+			responses:        []func(w http.ResponseWriter){status(http.StatusInternalServerError)},
+			expectedStatus:   http.StatusInternalServerError,
+			expectedRequests: maxRetries + 1,
+		},
+		{
+			name: "SecondaryRateLimitWithRetryAfter",
+			responses: []func(w http.ResponseWriter){
+				status(http.StatusForbidden, "Retry-After", "0"),
+				status(http.StatusOK),
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 2,
+		},
+		{
+			name: "TooManyRequestsWithRetryAfter",
+			responses: []func(w http.ResponseWriter){
+				status(http.StatusTooManyRequests, "Retry-After", "0"),
+				status(http.StatusOK),
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 2,
+		},
+		{
+			name: "PrimaryRateLimit",
+			responses: []func(w http.ResponseWriter){
+				status(http.StatusForbidden,
+					"X-RateLimit-Remaining", "0",
+					"X-RateLimit-Reset", fmt.Sprint(time.Now().Add(-time.Second).Unix())),
+				status(http.StatusOK),
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 2,
+		},
+		{
+			name:             "ForbiddenIsNotRetried",
+			responses:        []func(w http.ResponseWriter){status(http.StatusForbidden)},
+			expectedStatus:   http.StatusForbidden,
+			expectedRequests: 1,
+		},
+		{
+			name: "RequestsWithoutGetBodyAreNotRetried",
+			responses: []func(w http.ResponseWriter){
+				status(http.StatusBadGateway),
+				status(http.StatusOK),
+			},
+			expectedStatus:   http.StatusBadGateway,
+			expectedRequests: 1,
+			withoutGetBody:   true,
+		},
+	}
+	for _, tt := range testcases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var requests int32
+			server := httptest.NewServer(respondWith(&requests, tt.responses...))
+			t.Cleanup(server.Close)
+
+			client := &http.Client{
+				Transport: &rateLimitTransport{
+					logger:         zap.NewNop().Sugar(),
+					innerTransport: http.DefaultTransport,
+					backoff:        func(int) time.Duration { return 0 },
+				},
+			}
+			req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("body"))
+			if err != nil {
+				t.Fatalf("http.NewRequest: %v", err)
+			}
+			if tt.withoutGetBody {
+				req.GetBody = nil
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("client.Do: %v", err)
+			}
+			defer resp.Body.Close()
+			if _, err := ioutil.ReadAll(resp.Body); err != nil {
+				t.Fatalf("ioutil.ReadAll: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if requests != tt.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tt.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransportWaitIsCancellable(t *testing.T) {
+	t.Parallel()
+	var requests int32
+	server := httptest.NewServer(respondWith(&requests, status(http.StatusForbidden, "Retry-After", "60")))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{
+		Transport: MakeRateLimitedTransport(http.DefaultTransport, zap.NewNop().Sugar()),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequestWithContext: %v", err)
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Minute {
+		t.Errorf("wait was not cancelled, took %s", elapsed)
+	}
+}
+
+func TestRateLimitTransportRetryAfterIsBounded(t *testing.T) {
+	t.Parallel()
+	for _, retryAfter := range []string{"3600", "99999999999999999"} {
+		var requests int32
+		server := httptest.NewServer(respondWith(&requests, status(http.StatusTooManyRequests, "Retry-After", retryAfter)))
+		t.Cleanup(server.Close)
+
+		client := &http.Client{
+			Transport: MakeRateLimitedTransport(http.DefaultTransport, zap.NewNop().Sugar()),
+		}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+			t.Fatalf("Retry-After %s: expected error", retryAfter)
+		}
+		if !errors.Is(err, sce.ErrRateLimited) {
+			t.Errorf("Retry-After %s: expected ErrRateLimited, got: %v", retryAfter, err)
+		}
+		if requests != 1 {
+			t.Errorf("Retry-After %s: expected 1 request, got %d", retryAfter, requests)
+		}
+	}
+}
+
+func TestJitteredBackoff(t *testing.T) {
+	t.Parallel()
+	for retry := 0; retry < maxRetries; retry++ {
+		backoff := jitteredBackoff(retry)
+		min := baseBackoff << retry
+		if backoff < min || backoff >= 2*min {
+			t.Errorf("retry %d: backoff %s not in [%s, %s)", retry, backoff, min, 2*min)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	// CassetteDir is the directory to record HTTP interactions to, or replay them from.
+	CassetteDir = "SCORECARD_CASSETTE_DIR"
+	// CassetteMode is either CassetteRecord or CassetteReplay.
+	CassetteMode = "SCORECARD_CASSETTE_MODE"
+
+	// CassetteRecord sends requests to GitHub and records them in CassetteDir.
+	CassetteRecord = "record"
+	// CassetteReplay serves responses recorded in CassetteDir, without network access.
+	CassetteReplay = "replay"
+)
+
+const redacted = "REDACTED"
+
+var (
+	// ErrNoRecordedResponse indicates a request was not recorded in the cassette being replayed.
+	ErrNoRecordedResponse = errors.New("no recorded response for request")
+	// ErrEmptyCassette indicates the cassette to replay has no recorded interactions.
+	ErrEmptyCassette = errors.New("no recorded interactions in cassette")
+)
+
+var (
+	// Matches GitHub tokens, e.g. installation tokens returned by the GitHub API.
+	githubTokenPattern = regexp.MustCompile(`\bgh[opsur]_[A-Za-z0-9_]{20,}\b`)
+	// Matches the values of token query parameters, e.g. in the Location of redirects
+	// to the tarballs of private repos on codeload.github.com, and in the URLs they are
+	// then requested from.
+	queryTokenPattern = regexp.MustCompile(`([?&](?:access_)?token=)[^&#\s"]*`)
+
+	// Response headers which are not recorded.
+	droppedHeaders = []string{"Set-Cookie", "Authorization"}
+)
+
+// interaction is a recorded request and its response. Each interaction is stored as a
+// JSON file in the cassette directory.
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body,omitempty"`
+	BinaryBody []byte      `json:"binary_body,omitempty"`
+	StatusCode int         `json:"status_code"`
+}
+
+// key returns the string which replayed requests are matched on: the method, the URL
+// with sorted query parameters, and a digest of the body.
+func (r *recordedRequest) key() string {
+	key := r.Method + " " + r.URL
+	if r.Body != "" {
+		sum := sha256.Sum256([]byte(r.Body))
+		key += " " + hex.EncodeToString(sum[:])
+	}
+	return key
+}
+
+// MakeRecordingTransport returns a RoundTripper which sends requests using innerTransport
+// and records each request and its response as a file in dir. Occurrences of secrets and
+// of anything that looks like a GitHub token are replaced, and cookies and authorization
+// headers are not recorded. Recording into a directory with an earlier recording
+// overwrites it, but leaves behind interactions which are no longer made.
+func MakeRecordingTransport(innerTransport http.RoundTripper, dir string, secrets []string) http.RoundTripper {
+	return &recordingTransport{
+		innerTransport: innerTransport,
+		dir:            dir,
+		scrubber:       makeScrubber(secrets),
+		counts:         make(map[string]int),
+	}
+}
+
+type recordingTransport struct {
+	innerTransport http.RoundTripper
+	scrubber       *strings.Replacer
+	counts         map[string]int
+	dir            string
+	mu             sync.Mutex
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(r)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rt.innerTransport.RoundTrip(r)
+	if err != nil {
+		return nil, fmt.Errorf("error in HTTP: %w", err)
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	recorded := interaction{
+		Request: recordedRequest{
+			Method: r.Method,
+			URL:    rt.scrub(canonicalURL(r.URL)),
+			Body:   rt.scrub(string(reqBody)),
+		},
+		Response: recordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     make(http.Header),
+		},
+	}
+	for name, values := range resp.Header {
+		for _, value := range values {
+			recorded.Response.Header.Add(name, rt.scrub(value))
+		}
+	}
+	for _, name := range droppedHeaders {
+		recorded.Response.Header.Del(name)
+	}
+	if utf8.Valid(respBody) {
+		recorded.Response.Body = rt.scrub(string(respBody))
+	} else {
+		recorded.Response.BinaryBody = respBody
+	}
+
+	if err := rt.save(&recorded); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (rt *recordingTransport) scrub(s string) string {
+	return scrubQueryTokens(githubTokenPattern.ReplaceAllString(rt.scrubber.Replace(s), redacted))
+}
+
+// scrubQueryTokens replaces the values of token query parameters in s.
+func scrubQueryTokens(s string) string {
+	return queryTokenPattern.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// save writes recorded to a file named after its key, numbered in the order in which
+// requests with the same key were made.
+func (rt *recordingTransport) save(recorded *interaction) error {
+	key := recorded.Request.key()
+	rt.mu.Lock()
+	index := rt.counts[key]
+	rt.counts[key]++
+	rt.mu.Unlock()
+
+	content, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling interaction: %w", err)
+	}
+	sum := sha256.Sum256([]byte(key))
+	filename := filepath.Join(rt.dir, fmt.Sprintf("%s-%03d.json", hex.EncodeToString(sum[:8]), index))
+	// nolint: gomnd
+	if err := os.MkdirAll(rt.dir, 0o755); err != nil {
+		return fmt.Errorf("error creating cassette directory: %w", err)
+	}
+	// nolint: gomnd
+	if err := ioutil.WriteFile(filename, content, 0o644); err != nil {
+		return fmt.Errorf("error writing interaction: %w", err)
+	}
+	return nil
+}
+
+// MakeReplayTransport returns a RoundTripper which serves the responses recorded in dir by
+// a transport returned from MakeRecordingTransport. Requests are matched on their method,
+// URL and body. Responses to requests made several times are served in the order they were
+// recorded, repeating the last one once exhausted. Requests which were not recorded fail
+// with ErrNoRecordedResponse, and a dir without recorded interactions with ErrEmptyCassette.
+func MakeReplayTransport(dir string) (http.RoundTripper, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing cassette: %w", err)
+	}
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyCassette, dir)
+	}
+	// Filenames are numbered per key, so sorting them orders the responses to each request.
+	sort.Strings(filenames)
+	responses := make(map[string][]recordedResponse)
+	for _, filename := range filenames {
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("error reading interaction: %w", err)
+		}
+		var recorded interaction
+		if err := json.Unmarshal(content, &recorded); err != nil {
+			return nil, fmt.Errorf("error parsing interaction %s: %w", filename, err)
+		}
+		key := recorded.Request.key()
+		responses[key] = append(responses[key], recorded.Response)
+	}
+	return &replayTransport{
+		responses: responses,
+		served:    make(map[string]int),
+	}, nil
+}
+
+type replayTransport struct {
+	responses map[string][]recordedResponse
+	served    map[string]int
+	mu        sync.Mutex
+}
+
+func (rt *replayTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(r)
+	if err != nil {
+		return nil, err
+	}
+	// Tokens were scrubbed from the recorded URLs, and replayed redirects point to
+	// URLs with scrubbed tokens, but URLs built by the client may still have them.
+	req := recordedRequest{
+		Method: r.Method,
+		URL:    scrubQueryTokens(canonicalURL(r.URL)),
+		Body:   string(body),
+	}
+	key := req.key()
+
+	rt.mu.Lock()
+	responses := rt.responses[key]
+	index := rt.served[key]
+	if index < len(responses)-1 {
+		rt.served[key]++
+	}
+	rt.mu.Unlock()
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoRecordedResponse, r.Method, req.URL)
+	}
+
+	recorded := responses[index]
+	respBody := recorded.BinaryBody
+	if respBody == nil {
+		respBody = []byte(recorded.Body)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       r,
+	}, nil
+}
+
+// makeScrubber returns a Replacer which replaces each of secrets, and the individual
+// tokens of comma-separated secrets.
+func makeScrubber(secrets []string) *strings.Replacer {
+	var oldnew []string
+	for _, secret := range secrets {
+		for _, s := range append([]string{secret}, strings.Split(secret, ",")...) {
+			if s != "" {
+				oldnew = append(oldnew, s, redacted)
+			}
+		}
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// canonicalURL returns u with its query parameters sorted.
+func canonicalURL(u *url.URL) string {
+	canonical := *u
+	canonical.RawQuery = u.Query().Encode()
+	return canonical.String()
+}
+
+// readRequestBody returns the body of r, leaving it in place to be sent.
+func readRequestBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %w", err)
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bradleyfalzon/ghinstallation"
 	"go.uber.org/zap"
@@ -38,8 +39,13 @@ const (
 	GithubAppKeyPath = "GITHUB_APP_KEY_PATH"
 	// GithubAppID is the app ID for the GitHub App.
 	GithubAppID = "GITHUB_APP_ID"
-	// GithubAppInstallationID is the installation ID for the GitHub App.
+	// GithubAppInstallationID is the installation ID for the GitHub App. If it is
+	// GithubAppInstallationAuto, the installation of the GitHub App for the owner of each
+	// repository is used.
 	GithubAppInstallationID = "GITHUB_APP_INSTALLATION_ID"
+	// GithubAppInstallationAuto is the GithubAppInstallationID which looks up the
+	// installation for the owner of each repository.
+	GithubAppInstallationAuto = "auto"
 )
 
 func readGitHubTokens() (string, bool) {
@@ -51,15 +57,87 @@ func readGitHubTokens() (string, bool) {
 	return "", false
 }
 
+// makeBaseTransport returns the transport which sends requests over the network, or records
+// or replays them if CassetteMode is set.
+func makeBaseTransport() http.RoundTripper {
+	mode := os.Getenv(CassetteMode)
+	if mode == "" {
+		return http.DefaultTransport
+	}
+	dir := os.Getenv(CassetteDir)
+	if dir == "" {
+		log.Panicf("%s is set, but %s is not", CassetteMode, CassetteDir)
+	}
+	switch mode {
+	case CassetteRecord:
+		var secrets []string
+		for _, name := range GithubAuthTokens {
+			if secret := os.Getenv(name); secret != "" {
+				secrets = append(secrets, secret)
+			}
+		}
+		return MakeRecordingTransport(http.DefaultTransport, dir, secrets)
+	case CassetteReplay:
+		transport, err := MakeReplayTransport(dir)
+		if err != nil {
+			log.Panic(err)
+		}
+		return transport
+	default:
+		log.Panicf("invalid %s: %q, expected %q or %q", CassetteMode, mode, CassetteRecord, CassetteReplay)
+		return nil
+	}
+}
+
+// IsAnonymous returns true if neither GitHub tokens nor GitHub App credentials are set,
+// in which case NewTransport makes unauthenticated requests.
+func IsAnonymous() bool {
+	_, exists := readGitHubTokens()
+	return !exists && os.Getenv(GithubAppKeyPath) == ""
+}
+
 // NewTransport returns a configured http.Transport for use with GitHub.
+// Without credentials, the transport makes unauthenticated requests, which are subject
+// to a much lower rate limit and cannot use the GitHub GraphQL or code search APIs.
 func NewTransport(ctx context.Context, logger *zap.SugaredLogger) http.RoundTripper {
-	transport := http.DefaultTransport
+	return NewCachingTransport(ctx, logger, "", 0)
+}
+
+// NewCachingTransport returns a configured http.Transport for use with GitHub which
+// caches responses on disk in cacheDir, see MakeCachingTransport. If cacheDir is empty,
+// it is the same as NewTransport.
+func NewCachingTransport(ctx context.Context, logger *zap.SugaredLogger,
+	cacheDir string, ttl time.Duration) http.RoundTripper {
+	transport := makeBaseTransport()
 
+	token, hasTokens := readGitHubTokens()
+	keyPath := os.Getenv(GithubAppKeyPath)
 	// nolint
-	if token, exists := readGitHubTokens(); exists {
+	if keyPath != "" && os.Getenv(GithubAppInstallationID) == GithubAppInstallationAuto {
+		// Use the GitHub App installation of each owner, falling back to GitHub PATs if set.
+		appID, err := strconv.Atoi(os.Getenv(GithubAppID))
+		if err != nil {
+			log.Panic(err)
+		}
+		appsTransport, err := ghinstallation.NewAppsTransportKeyFromFile(transport, int64(appID), keyPath)
+		if err != nil {
+			log.Panic(err)
+		}
+		fallback := transport
+		if hasTokens {
+			fallback = githubrepo.MakeGitHubTransport(transport, strings.Split(token, ","))
+		} else {
+			logger.Warn("GitHub token env var is not set, repositories of owners without " +
+				"the GitHub App installed are accessed with unauthenticated requests.")
+		}
+		transport, err = githubrepo.MakeGitHubAppTransport(appsTransport, fallback)
+		if err != nil {
+			log.Panic(err)
+		}
+	} else if hasTokens {
 		// Use GitHub PAT
 		transport = githubrepo.MakeGitHubTransport(transport, strings.Split(token, ","))
-	} else if keyPath := os.Getenv(GithubAppKeyPath); keyPath != "" { // Also try a GITHUB_APP
+	} else if keyPath != "" { // Also try a GITHUB_APP
 		appID, err := strconv.Atoi(os.Getenv(GithubAppID))
 		if err != nil {
 			log.Panic(err)
@@ -73,9 +151,14 @@ func NewTransport(ctx context.Context, logger *zap.SugaredLogger) http.RoundTrip
 			log.Panic(err)
 		}
 	} else {
-		log.Fatalf("GitHub token env var is not set. " +
+		logger.Warn("GitHub token env var is not set, making unauthenticated requests. " +
 			"Please read https://github.com/ossf/scorecard#authentication")
 	}
 
+	// The cache wraps the authenticating transport so that responses are cached regardless
+	// of the token used to fetch them, which is fine since tokens are used round robin.
+	if cacheDir != "" {
+		transport = MakeCachingTransport(transport, cacheDir, ttl)
+	}
 	return MakeCensusTransport(MakeRateLimitedTransport(transport, logger))
 }
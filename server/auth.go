@@ -0,0 +1,200 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	opencensusstats "go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ossf/scorecard/v2/stats"
+)
+
+var (
+	errUnauthorized   = errors.New("unauthorized")
+	errQuotaExceeded  = errors.New("quota exceeded")
+	errInvalidAPIKeys = errors.New("invalid API keys")
+)
+
+// APIKey is a key which clients authenticate to the API with, and its quota.
+type APIKey struct {
+	// Name identifies the client in logs and metrics.
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+	// RequestsPerMinute is the rate at which the quota refills.
+	RequestsPerMinute float64 `yaml:"requests-per-minute"`
+	// Burst is the number of requests which may be made at once. It defaults to
+	// RequestsPerMinute, and is at least 1.
+	Burst int `yaml:"burst"`
+}
+
+// apiKeysFile is the format of the file read by LoadAPIKeys.
+type apiKeysFile struct {
+	Keys []APIKey `yaml:"keys"`
+}
+
+// LoadAPIKeys reads API keys from a YAML file of the form:
+//
+//	keys:
+//	- name: team-a
+//	  key: 0123456789abcdef
+//	  requests-per-minute: 60
+//	  burst: 10
+func LoadAPIKeys(path string) ([]APIKey, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading API keys: %w", err)
+	}
+	var file apiKeysFile
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return nil, fmt.Errorf("error during yaml.UnmarshalStrict: %w", err)
+	}
+	names := make(map[string]bool)
+	keys := make(map[string]bool)
+	for i := range file.Keys {
+		key := &file.Keys[i]
+		switch {
+		case key.Name == "" || key.Key == "":
+			return nil, fmt.Errorf("%w: key %d has no name or key", errInvalidAPIKeys, i)
+		case names[key.Name]:
+			return nil, fmt.Errorf("%w: duplicate name %s", errInvalidAPIKeys, key.Name)
+		case keys[key.Key]:
+			return nil, fmt.Errorf("%w: duplicate key for %s", errInvalidAPIKeys, key.Name)
+		case key.RequestsPerMinute <= 0 || key.Burst < 0:
+			return nil, fmt.Errorf("%w: %s must have a positive requests-per-minute and burst",
+				errInvalidAPIKeys, key.Name)
+		}
+		names[key.Name], keys[key.Key] = true, true
+	}
+	return file.Keys, nil
+}
+
+// apiClient is a client authenticated by an API key.
+type apiClient struct {
+	name    string
+	limiter *rate.Limiter
+}
+
+// authenticator authenticates requests by API key, and limits each key to its quota.
+type authenticator struct {
+	// clients are keyed by the SHA-256 of their key, so that looking keys up does
+	// not leak them through timing.
+	clients map[[sha256.Size]byte]*apiClient
+}
+
+func newAuthenticator(keys []APIKey) *authenticator {
+	a := &authenticator{clients: make(map[[sha256.Size]byte]*apiClient, len(keys))}
+	for _, key := range keys {
+		burst := key.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(key.RequestsPerMinute))
+		}
+		a.clients[sha256.Sum256([]byte(key.Key))] = &apiClient{
+			name:    key.Name,
+			limiter: rate.NewLimiter(rate.Limit(key.RequestsPerMinute/time.Minute.Seconds()), burst),
+		}
+	}
+	return a
+}
+
+// requestKey returns the API key of the request, which is sent as a bearer token
+// or in the X-API-Key header.
+func requestKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	const prefix = "bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix) {
+		return strings.TrimSpace(auth[len(prefix):])
+	}
+	return ""
+}
+
+// authPublic tells whether the path is served without an API key. Badges are
+// embedded in pages which cannot send keys, so they are served from the cache
+// unless the request has a key (see badgeMayScan), and webhook deliveries are signed.
+func authPublic(path string) bool {
+	return strings.HasPrefix(path, BadgePrefix) ||
+		path == APIPrefix+"webhooks/github" ||
+		path == APIPrefix+"openapi.yaml"
+}
+
+// authorize returns an error response if the request's API key is unknown or has
+// exceeded its quota.
+func (a *authenticator) authorize(r *http.Request) error {
+	return a.authorizeKey(r.Context(), requestKey(r))
+}
+
+// authorizeKey returns an error response if the key is unknown or has exceeded its
+// quota.
+func (a *authenticator) authorizeKey(ctx context.Context, key string) error {
+	client, ok := a.clients[sha256.Sum256([]byte(key))]
+	if !ok {
+		recordAPIRequest(ctx, "", stats.APIResultUnauthorized)
+		return newError(http.StatusUnauthorized, errUnauthorized, "missing or unknown API key")
+	}
+	reservation := client.limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		reservation.Cancel()
+		recordAPIRequest(ctx, client.name, stats.APIResultRateLimited)
+		return &quotaError{
+			httpError: newError(http.StatusTooManyRequests, errQuotaExceeded,
+				fmt.Sprintf("API key %s exceeded its quota", client.name)),
+			retryAfter: delay,
+		}
+	}
+	recordAPIRequest(ctx, client.name, stats.APIResultAllowed)
+	return nil
+}
+
+// quotaError is the error response of requests whose API key exceeded its quota.
+type quotaError struct {
+	*httpError
+	retryAfter time.Duration
+}
+
+func (e *quotaError) Unwrap() error {
+	return e.httpError
+}
+
+// retryAfterSeconds returns the Retry-After header of the error.
+func (e *quotaError) retryAfterSeconds() string {
+	return strconv.Itoa(int(math.Ceil(e.retryAfter.Seconds())))
+}
+
+func recordAPIRequest(ctx context.Context, name, result string) {
+	mutators := []tag.Mutator{tag.Upsert(stats.APIResult, result)}
+	if name != "" {
+		mutators = append(mutators, tag.Upsert(stats.APIKey, name))
+	}
+	ctx, err := tag.New(ctx, mutators...)
+	if err != nil {
+		return
+	}
+	opencensusstats.Record(ctx, stats.APIRequests.M(1))
+}
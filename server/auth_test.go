@@ -0,0 +1,165 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ossf/scorecard/v2/checker"
+)
+
+func TestLoadAPIKeys(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		path    string
+		want    []APIKey
+		wantErr error
+	}{
+		{
+			name: "valid",
+			path: "testdata/apikeys/valid.yaml",
+			want: []APIKey{
+				{Name: "team-a", Key: "key-a", RequestsPerMinute: 60, Burst: 2},
+				{Name: "team-b", Key: "key-b", RequestsPerMinute: 0.5},
+			},
+		},
+		{
+			name:    "duplicate key",
+			path:    "testdata/apikeys/duplicate.yaml",
+			wantErr: errInvalidAPIKeys,
+		},
+		{
+			name:    "no rate",
+			path:    "testdata/apikeys/no-rate.yaml",
+			wantErr: errInvalidAPIKeys,
+		},
+		{
+			name: "unknown field",
+			path: "testdata/apikeys/unknown-field.yaml",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Re-initializing variable so it is not changed while executing the closure below
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := LoadAPIKeys(tt.path)
+			if tt.want == nil {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("LoadAPIKeys() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadAPIKeys: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("keys mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+	scorer := &fakeScorer{}
+	check := func(c *checker.CheckRequest) checker.CheckResult { return checker.CheckResult{} }
+	srv, err := New(Options{
+		Logger:   zap.NewNop().Sugar(),
+		Score:    scorer.score,
+		Checks:   checker.CheckNameToFnMap{"Code-Review": check},
+		LogLevel: zapcore.InfoLevel,
+		APIKeys: []APIKey{
+			{Name: "team-a", Key: "key-a", RequestsPerMinute: 1, Burst: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	get := func(target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+	const repoTarget = "/api/v1/repos/github.com/ossf/scorecard"
+
+	steps := []struct {
+		name           string
+		target         string
+		header         http.Header
+		wantCode       int
+		wantRetryAfter string
+	}{
+		{
+			name:     "missing key",
+			target:   repoTarget,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "unknown key",
+			target:   repoTarget,
+			header:   http.Header{"Authorization": {"Bearer key-b"}},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "public OpenAPI description",
+			target:   "/api/v1/openapi.yaml",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "bearer token",
+			target:   repoTarget,
+			header:   http.Header{"Authorization": {"bearer key-a"}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "X-API-Key header",
+			target:   repoTarget,
+			header:   http.Header{"X-Api-Key": {"key-a"}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:           "burst exhausted",
+			target:         repoTarget,
+			header:         http.Header{"X-Api-Key": {"key-a"}},
+			wantCode:       http.StatusTooManyRequests,
+			wantRetryAfter: "60",
+		},
+	}
+	// Steps share the key's quota, so they run in order.
+	for _, step := range steps {
+		rec := get(step.target, step.header)
+		if rec.Code != step.wantCode {
+			t.Errorf("%s: status = %d, want %d: %s", step.name, rec.Code, step.wantCode, rec.Body)
+		}
+		if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: missing WWW-Authenticate header", step.name)
+		}
+		if got := rec.Header().Get("Retry-After"); step.wantRetryAfter != "" && got != step.wantRetryAfter {
+			t.Errorf("%s: Retry-After = %q, want %q", step.name, got, step.wantRetryAfter)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ossf/scorecard/v2/pkg"
+)
+
+const (
+	// BadgePrefix is the path prefix of badges.
+	BadgePrefix = "/badge/"
+
+	contentTypeSVG = "image/svg+xml"
+	// badgeCacheControl lets clients and proxies such as GitHub's image proxy cache
+	// badges briefly, so that they follow new results soon after a push.
+	badgeCacheControl = "public, max-age=300"
+)
+
+var (
+	// pendingBadge is served while a badge's repo is being scored.
+	pendingBadge = pkg.Badge{Label: "scorecard", Message: "pending", Color: pkg.BadgeColorGrey}
+	// unscoredBadge is served to requests which may not score a repo that has no
+	// cached result.
+	unscoredBadge = pkg.Badge{Label: "scorecard", Message: "?", Color: pkg.BadgeColorGrey}
+)
+
+// handleBadge serves `/badge/{host}/{owner}/{repo}.svg`, the badge of the aggregate
+// score of a repo, and `/badge/{host}/{owner}/{repo}/{check}.svg`, the badge of a
+// check. Badges are rendered from the results of all checks.
+func (s *Server) handleBadge(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethods(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+	const repoLen, checkLen = 3, 4
+	path := strings.TrimPrefix(r.URL.Path, BadgePrefix)
+	segments := strings.Split(strings.TrimSuffix(path, ".svg"), "/")
+	if !strings.HasSuffix(path, ".svg") || (len(segments) != repoLen && len(segments) != checkLen) {
+		s.writeError(w, r, newError(http.StatusNotFound, errRouteNotFound,
+			fmt.Sprintf("expected %s{host}/{owner}/{repo}.svg or %s{host}/{owner}/{repo}/{check}.svg",
+				BadgePrefix, BadgePrefix)))
+		return
+	}
+	params, err := s.makeParams(strings.Join(segments[:repoLen], "/"), nil)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	var checkName string
+	if len(segments) == checkLen {
+		checks, err := s.selectChecks(segments[repoLen:])
+		if err != nil {
+			s.writeError(w, r, err)
+			return
+		}
+		for name := range checks {
+			checkName = name
+		}
+	}
+
+	mayScan := s.badgeMayScan(r)
+	result, ok, err := s.badgeResult(r, &params, mayScan)
+	if err != nil {
+		s.opts.Logger.Error(err)
+		s.writeError(w, r, err)
+		return
+	}
+	if !ok {
+		w.Header().Set("Cache-Control", "no-cache")
+		if mayScan {
+			s.writeBadge(w, r, pendingBadge)
+		} else {
+			s.writeBadge(w, r, unscoredBadge)
+		}
+		return
+	}
+	w.Header().Set("Cache-Control", badgeCacheControl)
+	if checkName == "" {
+		s.writeBadge(w, r, result.AggregateBadge())
+		return
+	}
+	badge := pkg.Badge{Label: checkName, Message: "?", Color: pkg.BadgeColorGrey}
+	for i := range result.Checks {
+		if result.Checks[i].Name == checkName {
+			badge = pkg.CheckBadge(&result.Checks[i])
+		}
+	}
+	s.writeBadge(w, r, badge)
+}
+
+// badgeMayScan tells whether the badge request may score its repo when it has no
+// cached result. Badges are served without API keys, so that they can be embedded in
+// pages, but scoring a repo spends GitHub tokens: if API keys are required, only
+// requests with a key within its quota may do so.
+func (s *Server) badgeMayScan(r *http.Request) bool {
+	if s.auth == nil {
+		return true
+	}
+	return requestKey(r) != "" && s.auth.authorize(r) == nil
+}
+
+// badgeResult returns the cached result of the repo. If mayScan is false, it is the
+// latest cached result, since looking up the HEAD commit of the repo also spends
+// GitHub tokens, and false is returned if there is none. Otherwise, if there is no
+// result for the HEAD commit, the repo is scored in a scan job if jobs are enabled,
+// and false is returned, or it is scored before returning.
+func (s *Server) badgeResult(r *http.Request, params *repoParams,
+	mayScan bool) (pkg.ScorecardResult, bool, error) {
+	if !mayScan {
+		if s.opts.Cache == nil {
+			return pkg.ScorecardResult{}, false, nil
+		}
+		result, ok := s.opts.Cache.Latest(latestKey(params))
+		return result, ok, nil
+	}
+	if s.opts.Cache == nil || s.jobs == nil {
+		result, _, err := s.scan(r.Context(), params)
+		return result, err == nil, err
+	}
+	key, err := s.cacheKey(r.Context(), params)
+	if err != nil {
+		return pkg.ScorecardResult{}, false, err
+	}
+	if result, ok := s.opts.Cache.Get(key); ok {
+		return result, true, nil
+	}
+	if _, err := s.jobs.submitUnlessQueued(params); err != nil {
+		return pkg.ScorecardResult{}, false, err
+	}
+	return pkg.ScorecardResult{}, false, nil
+}
+
+// writeBadge writes the badge as SVG, with an ETag so that clients can revalidate it.
+func (s *Server) writeBadge(w http.ResponseWriter, r *http.Request, badge pkg.Badge) {
+	var buf bytes.Buffer
+	if err := badge.WriteSVG(&buf); err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeSVG)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		s.opts.Logger.Warn(err)
+	}
+}
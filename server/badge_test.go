@@ -0,0 +1,185 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+func scoreBadges(ctx context.Context, repo repos.RepoURL,
+	checks checker.CheckNameToFnMap) (pkg.ScorecardResult, error) {
+	result := pkg.ScorecardResult{Repo: repo.URL()}
+	for name := range checks {
+		score := 10
+		if name == "Fuzzing" {
+			score = 4
+		}
+		result.Checks = append(result.Checks, checker.CheckResult{Name: name, Score: score})
+	}
+	return result, nil
+}
+
+func getBadge(srv *Server, target, etag string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBadge(t *testing.T) {
+	t.Parallel()
+	srv, err := New(Options{
+		Logger: zap.NewNop().Sugar(),
+		Score:  scoreBadges,
+		Checks: checker.CheckNameToFnMap{"Code-Review": nil, "Fuzzing": nil},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	tests := []struct {
+		name        string
+		target      string
+		wantCode    int
+		wantMessage string
+	}{
+		{name: "aggregate", target: "/badge/github.com/ossf/scorecard.svg", wantCode: http.StatusOK, wantMessage: "7.0"},
+		{name: "check", target: "/badge/github.com/ossf/scorecard/fuzzing.svg", wantCode: http.StatusOK, wantMessage: "4/10"},
+		{name: "invalid check", target: "/badge/github.com/ossf/scorecard/Unknown.svg", wantCode: http.StatusBadRequest},
+		{name: "not svg", target: "/badge/github.com/ossf/scorecard.png", wantCode: http.StatusNotFound},
+		{name: "missing repo", target: "/badge/github.com/ossf.svg", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rec := getBadge(srv, tt.target, "")
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != contentTypeSVG {
+				t.Errorf("Content-Type = %s, want %s", got, contentTypeSVG)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != badgeCacheControl {
+				t.Errorf("Cache-Control = %s, want %s", got, badgeCacheControl)
+			}
+			if !strings.Contains(rec.Body.String(), ">"+tt.wantMessage+"<") {
+				t.Errorf("badge does not show %s: %s", tt.wantMessage, rec.Body)
+			}
+			etag := rec.Header().Get("ETag")
+			if rec := getBadge(srv, tt.target, etag); rec.Code != http.StatusNotModified {
+				t.Errorf("status with If-None-Match = %d, want %d", rec.Code, http.StatusNotModified)
+			}
+		})
+	}
+}
+
+func TestBadgePending(t *testing.T) {
+	t.Parallel()
+	srv, err := New(Options{
+		Logger: zap.NewNop().Sugar(),
+		Score:  scoreBadges,
+		Checks: checker.CheckNameToFnMap{
+			"Code-Review": func(c *checker.CheckRequest) checker.CheckResult { return checker.CheckResult{} },
+		},
+		Cache: NewResultCache(time.Hour, ""),
+		Jobs:  &JobOptions{},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	const target = "/badge/github.com/ossf/scorecard.svg"
+	rec := getBadge(srv, target, "")
+	if !strings.Contains(rec.Body.String(), ">pending<") || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatalf("unexpected badge before scoring: %s", rec.Body)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec = getBadge(srv, target, "")
+		if strings.Contains(rec.Body.String(), ">10.0<") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("badge was not updated after scoring: %s", rec.Body)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBadgeWithoutAPIKey(t *testing.T) {
+	t.Parallel()
+	scorer := &fakeScorer{}
+	var heads int
+	srv, err := New(Options{
+		Logger: zap.NewNop().Sugar(),
+		Score:  scorer.score,
+		Checks: checker.CheckNameToFnMap{
+			"Code-Review": func(c *checker.CheckRequest) checker.CheckResult { return checker.CheckResult{} },
+		},
+		Cache:   NewResultCache(time.Hour, ""),
+		APIKeys: []APIKey{{Name: "team-a", Key: "key-a", RequestsPerMinute: 60}},
+		Head: func(ctx context.Context, repo repos.RepoURL) (string, error) {
+			heads++
+			return "sha1", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	const target = "/badge/github.com/ossf/scorecard.svg"
+	rec := getBadge(srv, target, "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), ">?<") {
+		t.Fatalf("unexpected badge without a key on a cache miss: %d %s", rec.Code, rec.Body)
+	}
+	if scorer.called || heads != 0 {
+		t.Fatalf("badge request without a key scored the repo or looked up its HEAD commit %d times", heads)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("X-API-Key", "key-a")
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if !scorer.called || !strings.Contains(rec.Body.String(), ">0.0<") {
+		t.Fatalf("unexpected badge with a key: %s", rec.Body)
+	}
+
+	// Once the result is cached, it is served without a key, and without looking up
+	// the HEAD commit.
+	heads = 0
+	rec = getBadge(srv, target, "")
+	if !strings.Contains(rec.Body.String(), ">0.0<") || heads != 0 {
+		t.Fatalf("unexpected cached badge without a key, after %d HEAD lookups: %s", heads, rec.Body)
+	}
+}
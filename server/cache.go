@@ -0,0 +1,166 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/naveensrinivasan/httpcache"
+	"github.com/naveensrinivasan/httpcache/diskcache"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/pkg"
+)
+
+// ResultCache caches results in memory and, optionally, on disk so that they
+// survive restarts. Entries expire ttl after they were stored.
+type ResultCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	// latest maps keys without their `@<HEAD commit>` suffix to the key of the
+	// result stored last, in memory only.
+	latest map[string]string
+	disk   httpcache.Cache
+}
+
+// cacheEntry is a cached result and the time it was stored.
+type cacheEntry struct {
+	Result pkg.ScorecardResult
+	Stored time.Time
+}
+
+// NewResultCache returns a ResultCache whose entries expire after ttl.
+// If dir is not empty, entries are also stored in dir.
+func NewResultCache(ttl time.Duration, dir string) *ResultCache {
+	c := &ResultCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+		latest:  make(map[string]string),
+	}
+	if dir != "" {
+		c.disk = diskcache.New(dir)
+	}
+	return c
+}
+
+// Get returns the result cached with the key, if it has not expired. The checks of
+// the result are a copy, which callers may modify.
+func (c *ResultCache) Get(key string) (pkg.ScorecardResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.get(key)
+}
+
+// Latest returns the result stored last with a key which is latestKey followed by
+// `@<HEAD commit>`, if it has not expired, so that a result can be served without
+// looking up the HEAD commit.
+func (c *ResultCache) Latest(latestKey string) (pkg.ScorecardResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key, ok := c.latest[latestKey]
+	if !ok {
+		return pkg.ScorecardResult{}, false
+	}
+	return c.get(key)
+}
+
+func (c *ResultCache) get(key string) (pkg.ScorecardResult, bool) {
+	entry, ok := c.entries[key]
+	if !ok && c.disk != nil {
+		entry, ok = c.readDisk(key)
+	}
+	if !ok {
+		return pkg.ScorecardResult{}, false
+	}
+	if c.expired(entry) {
+		c.delete(key)
+		return pkg.ScorecardResult{}, false
+	}
+	c.entries[key] = entry
+	result := entry.Result
+	result.Checks = make([]checker.CheckResult, len(entry.Result.Checks))
+	copy(result.Checks, entry.Result.Checks)
+	return result, true
+}
+
+// Set caches the result with the key, and drops expired entries from memory.
+func (c *ResultCache) Set(key string, result pkg.ScorecardResult) {
+	entry := cacheEntry{Result: withoutErrors(result), Stored: time.Now()}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if c.expired(e) {
+			delete(c.entries, k)
+		}
+	}
+	for k, latest := range c.latest {
+		if _, ok := c.entries[latest]; !ok {
+			delete(c.latest, k)
+		}
+	}
+	c.entries[key] = entry
+	if i := strings.LastIndex(key, "@"); i >= 0 {
+		c.latest[key[:i]] = key
+	}
+	if c.disk != nil {
+		var buf bytes.Buffer
+		// Entries which cannot be encoded are only cached in memory.
+		if err := gob.NewEncoder(&buf).Encode(entry); err == nil {
+			c.disk.Set(key, buf.Bytes())
+		}
+	}
+}
+
+func (c *ResultCache) readDisk(key string) (cacheEntry, bool) {
+	data, ok := c.disk.Get(key)
+	if !ok {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		c.disk.Delete(key)
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *ResultCache) expired(entry cacheEntry) bool {
+	return time.Since(entry.Stored) > c.ttl
+}
+
+func (c *ResultCache) delete(key string) {
+	delete(c.entries, key)
+	if c.disk != nil {
+		c.disk.Delete(key)
+	}
+}
+
+// withoutErrors returns a copy of the result without the runtime errors of its checks,
+// which cannot be encoded. Their classes are kept in ErrorClass.
+func withoutErrors(result pkg.ScorecardResult) pkg.ScorecardResult {
+	checks := make([]checker.CheckResult, len(result.Checks))
+	for i := range result.Checks {
+		checks[i] = result.Checks[i]
+		checks[i].Error = nil
+		checks[i].Error2 = nil
+	}
+	result.Checks = checks
+	return result
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+func TestResultCache(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	result := pkg.ScorecardResult{
+		Repo: "github.com/ossf/scorecard",
+		Checks: []checker.CheckResult{{
+			Name: "Code-Review", Score: 7,
+			Error: errors.New("unencodable"), ErrorClass: "ErrScorecardInternal",
+		}},
+	}
+	NewResultCache(time.Hour, dir).Set("key", result)
+
+	// A new cache reads the entry stored on disk.
+	got, ok := NewResultCache(time.Hour, dir).Get("key")
+	if !ok {
+		t.Fatalf("Get() found no entry stored on disk")
+	}
+	if got.Checks[0].Score != 7 || got.Checks[0].ErrorClass != "ErrScorecardInternal" || got.Checks[0].Error != nil {
+		t.Errorf("unexpected cached check: %+v", got.Checks[0])
+	}
+
+	expiring := NewResultCache(-time.Second, dir)
+	if _, ok := expiring.Get("key"); ok {
+		t.Errorf("Get() returned an expired entry")
+	}
+	if _, ok := NewResultCache(time.Hour, dir).Get("key"); ok {
+		t.Errorf("expired entry was not deleted from disk")
+	}
+}
+
+func TestServeRepoCache(t *testing.T) {
+	t.Parallel()
+	var scans int32
+	release := make(chan struct{})
+	head := "sha1"
+	var headMu sync.Mutex
+	srv, err := New(Options{
+		Logger: zap.NewNop().Sugar(),
+		Score: func(ctx context.Context, repo repos.RepoURL,
+			checks checker.CheckNameToFnMap) (pkg.ScorecardResult, error) {
+			atomic.AddInt32(&scans, 1)
+			<-release
+			return pkg.ScorecardResult{Repo: repo.URL()}, nil
+		},
+		Checks: checker.CheckNameToFnMap{"Code-Review": nil},
+		Cache:  NewResultCache(time.Hour, ""),
+		Head: func(ctx context.Context, repo repos.RepoURL) (string, error) {
+			headMu.Lock()
+			defer headMu.Unlock()
+			return head, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		return rec
+	}
+	const target = "/api/v1/repos/github.com/ossf/scorecard"
+
+	// Concurrent requests share one scan.
+	const concurrent = 5
+	var wg sync.WaitGroup
+	wg.Add(concurrent)
+	for i := 0; i < concurrent; i++ {
+		go func() {
+			defer wg.Done()
+			get(target)
+		}()
+	}
+	for atomic.LoadInt32(&scans) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// Give the other requests time to join the scan before it finishes.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	tests := []struct {
+		name      string
+		target    string
+		newHead   string
+		wantCache string
+		wantScans int32
+	}{
+		{name: "cached", target: target, wantCache: "HIT", wantScans: 1},
+		{name: "refresh", target: target + "?refresh=true", wantCache: "MISS", wantScans: 2},
+		{name: "new commit", target: target, newHead: "sha2", wantCache: "MISS", wantScans: 3},
+		{name: "new commit cached", target: target, wantCache: "HIT", wantScans: 3},
+	}
+	for _, tt := range tests {
+		if tt.newHead != "" {
+			headMu.Lock()
+			head = tt.newHead
+			headMu.Unlock()
+		}
+		rec := get(tt.target)
+		if got := rec.Header().Get(xCache); got != tt.wantCache {
+			t.Errorf("%s: %s = %s, want %s", tt.name, xCache, got, tt.wantCache)
+		}
+		if got := atomic.LoadInt32(&scans); got != tt.wantScans {
+			t.Errorf("%s: %d scans, want %d", tt.name, got, tt.wantScans)
+		}
+	}
+}
+
+func TestServeRepoScanTimeout(t *testing.T) {
+	t.Parallel()
+	var scans int32
+	srv, err := New(Options{
+		Logger: zap.NewNop().Sugar(),
+		Score: func(ctx context.Context, repo repos.RepoURL,
+			checks checker.CheckNameToFnMap) (pkg.ScorecardResult, error) {
+			// The first scan hangs until it times out.
+			if atomic.AddInt32(&scans, 1) == 1 {
+				<-ctx.Done()
+				return pkg.ScorecardResult{}, ctx.Err()
+			}
+			return pkg.ScorecardResult{Repo: repo.URL()}, nil
+		},
+		Checks:      checker.CheckNameToFnMap{"Code-Review": nil},
+		ScanTimeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	const target = "/api/v1/repos/github.com/ossf/scorecard"
+	for _, want := range []int{http.StatusGatewayTimeout, http.StatusOK} {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != want {
+			t.Errorf("status = %d, want %d: %s", rec.Code, want, rec.Body)
+		}
+	}
+	if got := atomic.LoadInt32(&scans); got != 2 {
+		t.Errorf("%d scans, want 2", got)
+	}
+}
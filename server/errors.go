@@ -0,0 +1,146 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+var (
+	errInvalidRequest   = errors.New("invalid request")
+	errNotAcceptable    = errors.New("not acceptable")
+	errRouteNotFound    = errors.New("route not found")
+	errMethodNotAllowed = errors.New("method not allowed")
+)
+
+// requestErrorClasses names the errors of invalid requests, which are not errors.Classify classes.
+var requestErrorClasses = []struct {
+	err  error
+	name string
+}{
+	{errInvalidRequest, "ErrInvalidRequest"},
+	{errNotAcceptable, "ErrNotAcceptable"},
+	{errRouteNotFound, "ErrRouteNotFound"},
+	{errMethodNotAllowed, "ErrMethodNotAllowed"},
+	{errQueueFull, "ErrQueueFull"},
+	{errInvalidSignature, "ErrInvalidSignature"},
+	{errHistoryIndexing, "ErrHistoryIndexing"},
+	{errUnauthorized, "ErrUnauthorized"},
+	{errQuotaExceeded, "ErrQuotaExceeded"},
+}
+
+// retryAfter is the Retry-After header of 503 Service Unavailable responses, in seconds.
+const retryAfter = "60"
+
+// httpError is an error response of the API.
+type httpError struct {
+	Code    int    `json:"code"`
+	Class   string `json:"class"`
+	Message string `json:"message"`
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Code, e.Class, e.Message)
+}
+
+// errorBody is the JSON body of error responses.
+type errorBody struct {
+	Error *httpError `json:"error"`
+}
+
+// newError returns an error response with the class of err.
+func newError(code int, err error, msg string) *httpError {
+	class := sce.GetName(err)
+	for _, c := range requestErrorClasses {
+		if errors.Is(err, c.err) {
+			class = c.name
+		}
+	}
+	return &httpError{Code: code, Class: class, Message: msg}
+}
+
+// fromScoreError returns the error response for an error returned by a ScoreFunc.
+func fromScoreError(err error) *httpError {
+	var code int
+	switch sce.Classify(err) {
+	case sce.ErrNotFound:
+		code = http.StatusNotFound
+	case sce.ErrPermissionDenied:
+		code = http.StatusForbidden
+	case sce.ErrUnsupportedHost:
+		code = http.StatusBadRequest
+	case sce.ErrRateLimited:
+		code = http.StatusServiceUnavailable
+	case sce.ErrTimeout:
+		code = http.StatusGatewayTimeout
+	case nil:
+		return &httpError{Code: http.StatusInternalServerError, Class: "ErrScorecardInternal", Message: err.Error()}
+	default:
+		code = http.StatusInternalServerError
+	}
+	return newError(code, err, err.Error())
+}
+
+// toHTTPError returns err as an error response.
+func toHTTPError(err error) *httpError {
+	var resp *httpError
+	if errors.As(err, &resp) {
+		return resp
+	}
+	return fromScoreError(err)
+}
+
+// writeError writes err as the response, as JSON unless the client prefers HTML.
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	resp := toHTTPError(err)
+	var quota *quotaError
+	switch {
+	case errors.As(err, &quota):
+		w.Header().Set("Retry-After", quota.retryAfterSeconds())
+	case resp.Code == http.StatusServiceUnavailable:
+		w.Header().Set("Retry-After", retryAfter)
+	case resp.Code == http.StatusUnauthorized:
+		w.Header().Set("WWW-Authenticate", `Bearer realm="scorecard"`)
+	}
+	if format, err := negotiateFormat(r); err == nil && format == contentTypeHTML {
+		http.Error(w, fmt.Sprintf("%s: %s", http.StatusText(resp.Code), resp.Message), resp.Code)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(resp.Code)
+	if err := json.NewEncoder(w).Encode(errorBody{Error: resp}); err != nil {
+		s.opts.Logger.Warn(err)
+	}
+}
+
+// allowMethods writes a 405 response unless the request uses one of the methods.
+func (s *Server) allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	allow := strings.Join(methods, ", ")
+	w.Header().Set("Allow", allow)
+	s.writeError(w, r, newError(http.StatusMethodNotAllowed, errMethodNotAllowed, "allowed methods: "+allow))
+	return false
+}
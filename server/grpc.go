@@ -0,0 +1,287 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/cron/data"
+	"github.com/ossf/scorecard/v2/pkg"
+)
+
+// headRef names the HEAD of the default branch in ScoreRequest.ref.
+const headRef = "HEAD"
+
+var errUnsupportedRef = errors.New("unsupported ref")
+
+// grpcCodes maps the status codes of error responses to gRPC codes.
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusNotImplemented:      codes.Unimplemented,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// grpcService implements the Scorecard gRPC service with the checks, result cache
+// and ScoreFunc of a Server.
+type grpcService struct {
+	data.UnimplementedScorecardServer
+	server *Server
+}
+
+// NewGRPCServer returns a gRPC server which serves the Scorecard service, sharing the
+// checks, result cache, ScoreFunc and API keys of s, along with the standard health
+// and reflection services.
+func (s *Server) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	if s.auth != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(s.authorizeUnary),
+			grpc.ChainStreamInterceptor(s.authorizeStream))
+	}
+	g := grpc.NewServer(opts...)
+	data.RegisterScorecardServer(g, &grpcService{server: s})
+	healthpb.RegisterHealthServer(g, health.NewServer())
+	reflection.Register(g)
+	return g
+}
+
+// Score implements data.ScorecardServer.
+func (g *grpcService) Score(ctx context.Context, req *data.ScoreRequest) (*data.ScoreResponse, error) {
+	params, err := g.params(ctx, req)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	result, _, err := g.server.scan(ctx, &params)
+	if err != nil {
+		g.server.opts.Logger.Error(err)
+		return nil, toGRPCError(err)
+	}
+	resp := &data.ScoreResponse{
+		Repo:     result.Repo,
+		Date:     result.Date,
+		Metadata: result.Metadata,
+	}
+	checks := sortedChecks(result.Checks)
+	for i := range checks {
+		resp.Checks = append(resp.Checks, toProtoCheck(&checks[i], params.details, g.server.opts.LogLevel))
+	}
+	return resp, nil
+}
+
+// sortedChecks returns a copy of checks sorted by name. Results are shared by the
+// requests for the same scan, and with the cache, so they are not sorted in place.
+func sortedChecks(checks []checker.CheckResult) []checker.CheckResult {
+	sorted := make([]checker.CheckResult, len(checks))
+	copy(sorted, checks)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// ScoreStream implements data.ScorecardServer. Cached results are sent at once.
+// Otherwise each check is sent as soon as it completes, and the result is cached
+// once all of them did.
+func (g *grpcService) ScoreStream(req *data.ScoreRequest, stream data.Scorecard_ScoreStreamServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	params, err := g.params(ctx, req)
+	if err != nil {
+		return toGRPCError(err)
+	}
+	key, err := g.server.cacheKey(ctx, &params)
+	if err != nil {
+		return toGRPCError(err)
+	}
+	if g.server.opts.Cache != nil && !params.refresh {
+		if result, ok := g.server.opts.Cache.Get(key); ok {
+			checks := sortedChecks(result.Checks)
+			for i := range checks {
+				if err := stream.Send(toProtoCheck(&checks[i], params.details, g.server.opts.LogLevel)); err != nil {
+					//nolint:wrapcheck
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	var sendErr error
+	result, err := g.server.scoreStream(ctx, params.repo, params.checks, func(event pkg.CheckEvent) {
+		if event.Type != pkg.CheckFinished || sendErr != nil {
+			return
+		}
+		if sendErr = stream.Send(toProtoCheck(&event.Result, params.details, g.server.opts.LogLevel)); sendErr != nil {
+			// Stop the remaining checks.
+			cancel()
+		}
+	})
+	if sendErr != nil {
+		//nolint:wrapcheck
+		return sendErr
+	}
+	if err != nil {
+		g.server.opts.Logger.Error(err)
+		return toGRPCError(err)
+	}
+	if g.server.opts.Cache != nil {
+		g.server.opts.Cache.Set(key, result)
+	}
+	return nil
+}
+
+// params validates the request.
+func (g *grpcService) params(ctx context.Context, req *data.ScoreRequest) (repoParams, error) {
+	params, err := g.server.makeParams(req.GetRepo(), req.GetChecks())
+	if err != nil {
+		return params, err
+	}
+	params.details, params.refresh = req.GetDetails(), req.GetRefresh()
+	ref := req.GetRef()
+	if ref == "" || ref == headRef {
+		return params, nil
+	}
+	if g.server.opts.Head != nil {
+		head, err := g.server.opts.Head(ctx, params.repo)
+		if err != nil {
+			//nolint:wrapcheck
+			return params, err
+		}
+		if strings.EqualFold(ref, head) {
+			return params, nil
+		}
+	}
+	return params, newError(http.StatusNotImplemented, errUnsupportedRef,
+		fmt.Sprintf("only the HEAD of the default branch can be checked, not %s", ref))
+}
+
+// toProtoCheck converts a check result, keeping the details at logLevel or above
+// if details are requested.
+func toProtoCheck(check *checker.CheckResult, details bool, logLevel zapcore.Level) *data.CheckResult {
+	ret := &data.CheckResult{
+		Name:       check.Name,
+		Score:      int32(check.Score),
+		Reason:     check.Reason,
+		Pass:       check.Pass,
+		Confidence: int32(check.Confidence),
+		ErrorClass: check.ErrorClass,
+	}
+	if !details {
+		return ret
+	}
+	if len(check.Details2) == 0 {
+		ret.Details = check.Details
+		return ret
+	}
+	for _, detail := range check.Details2 {
+		if detail.Type == checker.DetailDebug && logLevel != zapcore.DebugLevel {
+			continue
+		}
+		ret.Details = append(ret.Details, fmt.Sprintf("%s: %s", detailTypeName(detail.Type), detail.Msg))
+	}
+	return ret
+}
+
+func detailTypeName(t checker.DetailType) string {
+	switch t {
+	case checker.DetailWarn:
+		return "Warn"
+	case checker.DetailDebug:
+		return "Debug"
+	default:
+		return "Info"
+	}
+}
+
+// toGRPCError returns err as a gRPC status with the code matching its error response.
+// The message is prefixed with the error class.
+func toGRPCError(err error) error {
+	resp := toHTTPError(err)
+	code, ok := grpcCodes[resp.Code]
+	if !ok {
+		code = codes.Unknown
+	}
+	//nolint:wrapcheck
+	return status.Error(code, fmt.Sprintf("%s: %s", resp.Class, resp.Message))
+}
+
+// requestKeyFromMetadata returns the API key of a gRPC call, which is sent as a
+// bearer token in the authorization metadata or in x-api-key.
+func requestKeyFromMetadata(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get("x-api-key"); len(keys) > 0 {
+		return keys[0]
+	}
+	const prefix = "bearer "
+	for _, auth := range md.Get("authorization") {
+		if len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix) {
+			return strings.TrimSpace(auth[len(prefix):])
+		}
+	}
+	return ""
+}
+
+// authorizeCall returns an error status if the call's API key is unknown or has
+// exceeded its quota. Health checks and reflection do not require a key.
+func (s *Server) authorizeCall(ctx context.Context, method string) error {
+	if !strings.HasPrefix(method, "/"+data.Scorecard_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+	if err := s.auth.authorizeKey(ctx, requestKeyFromMetadata(ctx)); err != nil {
+		var quota *quotaError
+		if errors.As(err, &quota) {
+			//nolint:errcheck
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", quota.retryAfterSeconds()))
+		}
+		return toGRPCError(err)
+	}
+	return nil
+}
+
+func (s *Server) authorizeUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorizeCall(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) authorizeStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	if err := s.authorizeCall(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
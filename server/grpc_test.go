@@ -0,0 +1,317 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/cron/data"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+var errReleaseTimeout = errors.New("check was not released")
+
+// runChecks is a ScoreFunc which runs the check functions, as the engine does.
+func runChecks(ctx context.Context, repo repos.RepoURL,
+	checks checker.CheckNameToFnMap) (pkg.ScorecardResult, error) {
+	result := pkg.ScorecardResult{Repo: repo.URL(), Date: "2021-08-01"}
+	for _, fn := range checks {
+		result.Checks = append(result.Checks, fn(&checker.CheckRequest{Ctx: ctx}))
+	}
+	return result, nil
+}
+
+func scoreCheck(name string, score int) checker.CheckFn {
+	return func(c *checker.CheckRequest) checker.CheckResult {
+		result := checker.CreateResultWithScore(name, "reason", score)
+		result.Details2 = []checker.CheckDetail{
+			{Type: checker.DetailWarn, Msg: "warning"},
+			{Type: checker.DetailDebug, Msg: "debug"},
+		}
+		return result
+	}
+}
+
+func newGRPCClient(t *testing.T, opts Options) data.ScorecardClient {
+	t.Helper()
+	opts.Logger = zap.NewNop().Sugar()
+	opts.LogLevel = zapcore.InfoLevel
+	opts.Checks = checker.CheckNameToFnMap{
+		"Code-Review":     scoreCheck("Code-Review", 7),
+		"Signed-Releases": scoreCheck("Signed-Releases", 3),
+	}
+	srv, err := New(opts)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	const bufSize = 1024 * 1024
+	listener := bufconn.Listen(bufSize)
+	g := srv.NewGRPCServer()
+	go func() {
+		//nolint:errcheck
+		g.Serve(listener)
+	}()
+	t.Cleanup(g.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("grpc.Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return data.NewScorecardClient(conn)
+}
+
+func TestGRPCScore(t *testing.T) {
+	t.Parallel()
+	client := newGRPCClient(t, Options{
+		Score: runChecks,
+		Head: func(ctx context.Context, repo repos.RepoURL) (string, error) {
+			return "0123abcd", nil
+		},
+	})
+	tests := []struct {
+		name     string
+		req      *data.ScoreRequest
+		wantCode codes.Code
+		want     *data.ScoreResponse
+	}{
+		{
+			name: "all checks",
+			req:  &data.ScoreRequest{Repo: "github.com/ossf/scorecard"},
+			want: &data.ScoreResponse{
+				Repo: "github.com/ossf/scorecard",
+				Date: "2021-08-01",
+				Checks: []*data.CheckResult{
+					{Name: "Code-Review", Score: 7, Reason: "reason", Confidence: 10},
+					{Name: "Signed-Releases", Score: 3, Reason: "reason", Confidence: 10},
+				},
+			},
+		},
+		{
+			name: "selected check with details at HEAD commit",
+			req: &data.ScoreRequest{
+				Repo: "github.com/ossf/scorecard", Checks: []string{"code-review"}, Ref: "0123ABCD", Details: true,
+			},
+			want: &data.ScoreResponse{
+				Repo: "github.com/ossf/scorecard",
+				Date: "2021-08-01",
+				Checks: []*data.CheckResult{
+					{
+						Name: "Code-Review", Score: 7, Reason: "reason", Confidence: 10,
+						Details: []string{"Warn: warning"},
+					},
+				},
+			},
+		},
+		{
+			name:     "invalid check",
+			req:      &data.ScoreRequest{Repo: "github.com/ossf/scorecard", Checks: []string{"Unknown"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "other ref",
+			req:      &data.ScoreRequest{Repo: "github.com/ossf/scorecard", Ref: "v1.0.0"},
+			wantCode: codes.Unimplemented,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Re-initializing variable so it is not changed while executing the closure below
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := client.Score(context.Background(), tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Score() error = %v, want code %v", err, tt.wantCode)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("response mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// streamChecks returns the names of the checks sent by ScoreStream. It may be called
+// from goroutines other than the test's.
+func streamChecks(t *testing.T, client data.ScorecardClient, req *data.ScoreRequest) []string {
+	t.Helper()
+	stream, err := client.ScoreStream(context.Background(), req)
+	if err != nil {
+		t.Errorf("ScoreStream: %v", err)
+		return nil
+	}
+	var ret []string
+	for {
+		result, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return ret
+		}
+		if err != nil {
+			t.Errorf("Recv: %v", err)
+			return ret
+		}
+		ret = append(ret, result.GetName())
+	}
+}
+
+func TestGRPCScoreStream(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	sent := make(chan string, 2)
+	scores := 0
+	client := newGRPCClient(t, Options{
+		// Checks run one after the other, and the first is only released once it was sent.
+		Score: func(ctx context.Context, repo repos.RepoURL,
+			checks checker.CheckNameToFnMap) (pkg.ScorecardResult, error) {
+			scores++
+			result := pkg.ScorecardResult{Repo: repo.URL()}
+			result.Checks = append(result.Checks, checks["Code-Review"](&checker.CheckRequest{}))
+			select {
+			case <-release:
+			case <-time.After(10 * time.Second):
+				return result, errReleaseTimeout
+			}
+			result.Checks = append(result.Checks, checks["Signed-Releases"](&checker.CheckRequest{}))
+			return result, nil
+		},
+		Cache: NewResultCache(time.Hour, ""),
+	})
+
+	stream, err := client.ScoreStream(context.Background(), &data.ScoreRequest{Repo: "github.com/ossf/scorecard"})
+	if err != nil {
+		t.Fatalf("ScoreStream: %v", err)
+	}
+	go func() {
+		for {
+			result, err := stream.Recv()
+			if err != nil {
+				close(sent)
+				return
+			}
+			sent <- result.GetName()
+		}
+	}()
+	if first := <-sent; first != "Code-Review" {
+		t.Errorf("first check = %s, want Code-Review", first)
+	}
+	close(release)
+	if second := <-sent; second != "Signed-Releases" {
+		t.Errorf("second check = %s, want Signed-Releases", second)
+	}
+
+	// The result was cached, so it is sent without scoring again.
+	got := streamChecks(t, client, &data.ScoreRequest{Repo: "github.com/ossf/scorecard"})
+	if diff := cmp.Diff([]string{"Code-Review", "Signed-Releases"}, got); diff != "" {
+		t.Errorf("cached checks mismatch (-want +got):\n%s", diff)
+	}
+	if scores != 1 {
+		t.Errorf("scored %d times, want 1", scores)
+	}
+}
+
+func TestGRPCScoreConcurrent(t *testing.T) {
+	t.Parallel()
+	const calls = 8
+	client := newGRPCClient(t, Options{
+		// Checks are returned out of order, and scans are slow enough for the
+		// concurrent calls to share them.
+		Score: func(ctx context.Context, repo repos.RepoURL,
+			checks checker.CheckNameToFnMap) (pkg.ScorecardResult, error) {
+			time.Sleep(50 * time.Millisecond)
+			return pkg.ScorecardResult{
+				Repo: repo.URL(),
+				Checks: []checker.CheckResult{
+					checks["Signed-Releases"](&checker.CheckRequest{}),
+					checks["Code-Review"](&checker.CheckRequest{}),
+				},
+			}, nil
+		},
+		Cache: NewResultCache(time.Hour, ""),
+	})
+	want := []string{"Code-Review", "Signed-Releases"}
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Score(context.Background(), &data.ScoreRequest{Repo: "github.com/ossf/scorecard"})
+			if err != nil {
+				t.Errorf("Score: %v", err)
+				return
+			}
+			var got []string
+			for _, check := range resp.GetChecks() {
+				got = append(got, check.GetName())
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("checks mismatch (-want +got):\n%s", diff)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			// Streams started after the first scan are sent the cached result.
+			time.Sleep(100 * time.Millisecond)
+			got := streamChecks(t, client, &data.ScoreRequest{Repo: "github.com/ossf/scorecard"})
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("streamed checks mismatch (-want +got):\n%s", diff)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGRPCAuthenticate(t *testing.T) {
+	t.Parallel()
+	client := newGRPCClient(t, Options{
+		Score:   runChecks,
+		APIKeys: []APIKey{{Name: "team-a", Key: "key-a", RequestsPerMinute: 1, Burst: 1}},
+	})
+	req := &data.ScoreRequest{Repo: "github.com/ossf/scorecard"}
+
+	if _, err := client.Score(context.Background(), req); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Score() without key error = %v, want Unauthenticated", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer key-a")
+	if _, err := client.Score(ctx, req); err != nil {
+		t.Errorf("Score() with key: %v", err)
+	}
+	var header metadata.MD
+	_, err := client.Score(ctx, req, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Score() over quota error = %v, want ResourceExhausted", err)
+	}
+	if got := header.Get("retry-after"); len(got) != 1 || got[0] != "60" {
+		t.Errorf("retry-after = %v, want [60]", got)
+	}
+}
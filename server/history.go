@@ -0,0 +1,528 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gocloud.dev/blob"
+
+	// Needed to read buckets on the local filesystem.
+	_ "gocloud.dev/blob/fileblob"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/cron/data"
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+const (
+	defaultHistoryRefreshInterval = time.Hour
+
+	// shardPrefix is the prefix of the names of the result shards written by the cron
+	// worker, as opposed to its shard_num and transfer_status files.
+	shardPrefix = "shard-"
+	// maxShardLine bounds the size of a result in a shard.
+	maxShardLine = 64 * 1024 * 1024
+)
+
+var (
+	errHistoryIndexing = errors.New("history indexing")
+	errRepoNotInShard  = errors.New("repo not found in shard")
+)
+
+// HistoryOptions configure the history of results read from the bucket written by
+// the cron job, where each run writes `YYYY.MM.DD/HHMMSS/shard-NNNNN` files of
+// newline-delimited JSON results.
+type HistoryOptions struct {
+	// BucketURL is the URL of the bucket, with any gocloud.dev/blob scheme which
+	// is linked in, e.g. `gs://ossf-scorecard-data` or `file:///var/lib/scorecard`.
+	BucketURL string
+	// RefreshInterval is how often shards written since the last refresh are indexed.
+	RefreshInterval time.Duration
+}
+
+// ScorePoint is the scores a repo got in a cron run.
+type ScorePoint struct {
+	// Time is the time the cron run started at.
+	Time time.Time `json:"time"`
+	// Score is the average score of the conclusive checks, or null if there are none.
+	Score *float64 `json:"score"`
+	// Checks maps each check to its score. Inconclusive checks score -1.
+	Checks map[string]int `json:"checks"`
+}
+
+// ScoreSeries is the scores a repo got in the cron runs, oldest first.
+type ScoreSeries struct {
+	Repo   string       `json:"repo"`
+	Points []ScorePoint `json:"points"`
+}
+
+// historyShard is an indexed shard.
+type historyShard struct {
+	key string
+	// time is the time the cron run which wrote the shard started at.
+	time time.Time
+}
+
+// history indexes the results in the cron bucket by repo. Only the shards of the
+// results of each repo are kept in memory, as the cron job scores millions of repos
+// in each run, and results are read from their shards when they are requested.
+type history struct {
+	opts   HistoryOptions
+	server *Server
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu sync.RWMutex
+	// loaded is set once the bucket was listed successfully.
+	loaded bool
+	// shards are the indexed shards. Shards are never rewritten.
+	shards  []historyShard
+	indexed map[string]bool
+	// repos maps the lowercase repo of each result to the indexes in shards of the
+	// shards with its results, oldest first.
+	repos map[string][]int32
+}
+
+// newHistory starts indexing the bucket in the background, and then refreshing the
+// index every opts.RefreshInterval.
+func newHistory(server *Server, opts HistoryOptions) *history {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = defaultHistoryRefreshInterval
+	}
+	h := &history{
+		opts:    opts,
+		server:  server,
+		indexed: make(map[string]bool),
+		repos:   make(map[string][]int32),
+	}
+	h.ctx, h.cancel = context.WithCancel(context.Background())
+	h.wg.Add(1)
+	go h.run()
+	return h
+}
+
+// close stops refreshing the index.
+func (h *history) close() {
+	h.cancel()
+	h.wg.Wait()
+}
+
+func (h *history) run() {
+	defer h.wg.Done()
+	ticker := time.NewTicker(h.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := h.refresh(h.ctx); err != nil {
+			h.server.opts.Logger.Error(err)
+		}
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh indexes the shards which are not indexed yet. Shards which cannot be
+// read are logged and retried on the next refresh.
+func (h *history) refresh(ctx context.Context) error {
+	bucket, err := blob.OpenBucket(ctx, h.opts.BucketURL)
+	if err != nil {
+		return fmt.Errorf("error from blob.OpenBucket: %w", err)
+	}
+	defer bucket.Close()
+
+	iter := bucket.List(nil)
+	for {
+		next, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			h.mu.Lock()
+			h.loaded = true
+			h.mu.Unlock()
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error during iter.Next: %w", err)
+		}
+		h.mu.RLock()
+		indexed := h.indexed[next.Key]
+		h.mu.RUnlock()
+		if indexed || next.IsDir {
+			continue
+		}
+		jobTime, name, err := data.ParseBlobFilename(next.Key)
+		if err != nil || !strings.HasPrefix(name, shardPrefix) {
+			continue
+		}
+		if err := h.index(ctx, bucket, next.Key, jobTime); err != nil {
+			h.server.opts.Logger.Warnf("error indexing shard %s: %v", next.Key, err)
+		}
+	}
+}
+
+// index adds the results in the shard to the index.
+func (h *history) index(ctx context.Context, bucket *blob.Bucket, key string, jobTime time.Time) error {
+	repos := make(map[string]bool)
+	err := readShard(ctx, bucket, key, func(result *pkg.ScorecardResult) bool {
+		repos[strings.ToLower(result.Repo)] = true
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.indexed[key] {
+		// Indexed by a concurrent refresh.
+		return nil
+	}
+	shard := int32(len(h.shards))
+	h.shards = append(h.shards, historyShard{key: key, time: jobTime})
+	for repo := range repos {
+		shards := append(h.repos[repo], shard)
+		sort.SliceStable(shards, func(i, j int) bool {
+			return h.shards[shards[i]].time.Before(h.shards[shards[j]].time)
+		})
+		h.repos[repo] = shards
+	}
+	h.indexed[key] = true
+	return nil
+}
+
+// readShard calls fn with each result in the shard until fn returns false.
+func readShard(ctx context.Context, bucket *blob.Bucket, key string, fn func(*pkg.ScorecardResult) bool) error {
+	reader, err := bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		return fmt.Errorf("error from bucket.NewReader: %w", err)
+	}
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxShardLine)
+	for scanner.Scan() {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var result pkg.ScorecardResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return fmt.Errorf("error during json.Unmarshal: %w", err)
+		}
+		if !fn(&result) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading shard: %w", err)
+	}
+	return nil
+}
+
+// scorePoint returns the scores of a result of the cron run started at jobTime.
+func scorePoint(result *pkg.ScorecardResult, jobTime time.Time) ScorePoint {
+	point := ScorePoint{Time: jobTime, Checks: make(map[string]int, len(result.Checks))}
+	for i := range result.Checks {
+		score := historyScore(&result.Checks[i])
+		result.Checks[i].Score = score
+		point.Checks[result.Checks[i].Name] = score
+	}
+	if score, ok := result.AggregateScore(); ok {
+		point.Score = &score
+	}
+	return point
+}
+
+// historyScore returns the score of a check read from the bucket. Shards only keep
+// whether checks passed and their confidence, so passing checks score 10, failing
+// ones 0, and checks which errored or had no confidence are inconclusive.
+func historyScore(check *checker.CheckResult) int {
+	switch {
+	case check.ErrorClass != "" || (!check.Pass && check.Confidence == 0):
+		return checker.InconclusiveResultScore
+	case check.Pass:
+		return checker.MaxResultScore
+	default:
+		return checker.MinResultScore
+	}
+}
+
+// repoShards returns the shards with results of the repo, oldest first.
+func (h *history) repoShards(repo *repos.RepoURL) ([]historyShard, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if !h.loaded {
+		return nil, newError(http.StatusServiceUnavailable, errHistoryIndexing,
+			"the history is not indexed yet, try again later")
+	}
+	indexes := h.repos[strings.ToLower(repo.URL())]
+	if len(indexes) == 0 {
+		return nil, newError(http.StatusNotFound, sce.ErrNotFound,
+			fmt.Sprintf("no results in the history of %s", repo.URL()))
+	}
+	ret := make([]historyShard, 0, len(indexes))
+	for _, i := range indexes {
+		ret = append(ret, h.shards[i])
+	}
+	return ret, nil
+}
+
+// readResult reads the result of the repo from the shard, with the scores of its
+// checks set, and returns the scores.
+func readResult(ctx context.Context, bucket *blob.Bucket, shard historyShard,
+	repo *repos.RepoURL) (pkg.ScorecardResult, ScorePoint, error) {
+	var ret pkg.ScorecardResult
+	var point ScorePoint
+	found := false
+	err := readShard(ctx, bucket, shard.key, func(result *pkg.ScorecardResult) bool {
+		if !strings.EqualFold(result.Repo, repo.URL()) {
+			return true
+		}
+		ret, found = *result, true
+		point = scorePoint(&ret, shard.time)
+		return false
+	})
+	if err != nil {
+		return pkg.ScorecardResult{}, ScorePoint{}, err
+	}
+	if !found {
+		return pkg.ScorecardResult{}, ScorePoint{}, fmt.Errorf("%w: %s", errRepoNotInShard, shard.key)
+	}
+	return ret, point, nil
+}
+
+// latest returns the most recent result of the repo.
+func (h *history) latest(ctx context.Context, repo *repos.RepoURL) (pkg.ScorecardResult, error) {
+	shards, err := h.repoShards(repo)
+	if err != nil {
+		return pkg.ScorecardResult{}, err
+	}
+	bucket, err := blob.OpenBucket(ctx, h.opts.BucketURL)
+	if err != nil {
+		return pkg.ScorecardResult{}, fmt.Errorf("error from blob.OpenBucket: %w", err)
+	}
+	defer bucket.Close()
+	result, _, err := readResult(ctx, bucket, shards[len(shards)-1], repo)
+	return result, err
+}
+
+// series returns the scores of the repo, keeping only the checks in names, matched
+// case-insensitively, unless names is empty.
+func (h *history) series(ctx context.Context, repo *repos.RepoURL, names []string) (ScoreSeries, error) {
+	shards, err := h.repoShards(repo)
+	if err != nil {
+		return ScoreSeries{}, err
+	}
+	bucket, err := blob.OpenBucket(ctx, h.opts.BucketURL)
+	if err != nil {
+		return ScoreSeries{}, fmt.Errorf("error from blob.OpenBucket: %w", err)
+	}
+	defer bucket.Close()
+	ret := ScoreSeries{Repo: repo.URL(), Points: make([]ScorePoint, 0, len(shards))}
+	for _, shard := range shards {
+		_, point, err := readResult(ctx, bucket, shard, repo)
+		if err != nil {
+			return ScoreSeries{}, err
+		}
+		if len(names) > 0 {
+			checks := point.Checks
+			point.Checks = make(map[string]int, len(names))
+			for name, score := range checks {
+				for _, want := range names {
+					if strings.EqualFold(name, want) {
+						point.Checks[name] = score
+					}
+				}
+			}
+		}
+		ret.Points = append(ret.Points, point)
+	}
+	return ret, nil
+}
+
+// handleHistory serves `/api/v1/history/{host}/{owner}/{repo}/latest`, the latest
+// result of a repo in the cron bucket, and `/api/v1/history/{host}/{owner}/{repo}/scores`,
+// the scores it got in each cron run.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethods(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+	format, err := negotiateFormat(r)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	const length = 4
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, APIPrefix+"history/"), "/")
+	if len(segments) != length || segments[0] == "" || segments[1] == "" || segments[2] == "" ||
+		(segments[3] != "latest" && segments[3] != "scores") {
+		s.writeError(w, r, newError(http.StatusNotFound, errRouteNotFound,
+			fmt.Sprintf("expected %shistory/{host}/{owner}/{repo}/latest or %shistory/{host}/{owner}/{repo}/scores",
+				APIPrefix, APIPrefix)))
+		return
+	}
+	var repo repos.RepoURL
+	if err := repo.Set(strings.Join(segments[:3], "/")); err != nil {
+		s.writeError(w, r, newError(http.StatusBadRequest, errInvalidRequest, err.Error()))
+		return
+	}
+
+	if segments[3] == "latest" {
+		s.serveLatest(w, r, format, &repo)
+		return
+	}
+	series, err := s.history.series(r.Context(), &repo, queryList(r, "checks"))
+	if err != nil {
+		s.opts.Logger.Error(err)
+		s.writeError(w, r, err)
+		return
+	}
+	if format != contentTypeHTML {
+		w.Header().Set("Vary", "Accept")
+		s.writeJSON(w, http.StatusOK, series)
+		return
+	}
+	var buf bytes.Buffer
+	if err := s.scoresPage.Execute(&buf, newScoresPage(&series)); err != nil {
+		s.opts.Logger.Error(err)
+		s.writeError(w, r, newError(http.StatusInternalServerError, sce.ErrScorecardInternal, err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeHTML+"; charset=utf-8")
+	w.Header().Set("Vary", "Accept")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		s.opts.Logger.Warn(err)
+	}
+}
+
+func (s *Server) serveLatest(w http.ResponseWriter, r *http.Request, format string, repo *repos.RepoURL) {
+	var details bool
+	if raw := r.URL.Query().Get("details"); raw != "" {
+		var err error
+		if details, err = strconv.ParseBool(raw); err != nil {
+			s.writeError(w, r, newError(http.StatusBadRequest, errInvalidRequest,
+				fmt.Sprintf("invalid details query parameter: %q", raw)))
+			return
+		}
+	}
+	result, err := s.history.latest(r.Context(), repo)
+	if err != nil {
+		s.opts.Logger.Error(err)
+		s.writeError(w, r, err)
+		return
+	}
+	s.writeResult(w, r, format, &result, details)
+}
+
+// scoresPage is the data of scoresTemplate.
+type scoresPage struct {
+	Repo   string
+	Checks []string
+	Rows   []scoresRow
+	// Line is the SVG polyline of the aggregate scores.
+	Line string
+}
+
+type scoresRow struct {
+	Time   string
+	Score  string
+	Checks []string
+}
+
+const (
+	chartWidth  = 600
+	chartHeight = 100
+)
+
+func newScoresPage(series *ScoreSeries) scoresPage {
+	page := scoresPage{Repo: series.Repo}
+	seen := make(map[string]bool)
+	for _, point := range series.Points {
+		for name := range point.Checks {
+			if !seen[name] {
+				seen[name] = true
+				page.Checks = append(page.Checks, name)
+			}
+		}
+	}
+	sort.Strings(page.Checks)
+
+	var line []string
+	for i, point := range series.Points {
+		row := scoresRow{Time: point.Time.Format("2006-01-02 15:04"), Score: "?"}
+		if point.Score != nil {
+			row.Score = fmt.Sprintf("%.1f", *point.Score)
+			x := chartWidth
+			if len(series.Points) > 1 {
+				x = i * chartWidth / (len(series.Points) - 1)
+			}
+			y := chartHeight - *point.Score*chartHeight/checker.MaxResultScore
+			line = append(line, fmt.Sprintf("%d,%.1f", x, y))
+		}
+		for _, name := range page.Checks {
+			score, ok := point.Checks[name]
+			if !ok || score == checker.InconclusiveResultScore {
+				row.Checks = append(row.Checks, "?")
+				continue
+			}
+			row.Checks = append(row.Checks, strconv.Itoa(score))
+		}
+		page.Rows = append(page.Rows, row)
+	}
+	page.Line = strings.Join(line, " ")
+	return page
+}
+
+const scoresTemplate = `
+<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="UTF-8">
+		<title>Scorecard History for: {{.Repo}}</title>
+	</head>
+	<body>
+		<h1>{{.Repo}}</h1>
+		<svg width="600" height="100" viewBox="-5 -5 610 110">
+			<polyline fill="none" stroke="#4c1" stroke-width="2" points="{{.Line}}"/>
+		</svg>
+		<table>
+			<tr>
+				<th>Time</th><th>Score</th>
+				{{range .Checks}}<th>{{.}}</th>{{end}}
+			</tr>
+			{{range .Rows}}
+				<tr>
+					<td>{{.Time}}</td><td>{{.Score}}</td>
+					{{range .Checks}}<td>{{.}}</td>{{end}}
+				</tr>
+			{{end}}
+		</table>
+	</body>
+</html>`
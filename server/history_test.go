@@ -0,0 +1,238 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/cron/data"
+)
+
+// Shards as written by the cron worker, which only keeps the pass/confidence fields.
+var historyShards = map[string]string{
+	"2021.08.01/000000/shard-00000": `{"Repo":"github.com/ossf/scorecard","Date":"2021-08-01","Checks":[` +
+		`{"Name":"Code-Review","Details":["old"],"Confidence":10,"Pass":false},` +
+		`{"Name":"Signed-Releases","Details":null,"Confidence":0,"Pass":false}],"Metadata":null}
+{"Repo":"github.com/other/repo","Date":"2021-08-01","Checks":[],"Metadata":null}
+`,
+	"2021.08.01/000000/shard_num": "1",
+	"2021.08.08/000000/shard-00000": `{"Repo":"github.com/ossf/scorecard","Date":"2021-08-08","Checks":[` +
+		`{"Name":"Code-Review","Details":["new"],"Confidence":10,"Pass":true},` +
+		`{"Name":"Signed-Releases","Details":null,"Confidence":7,"Pass":false}],"Metadata":null}
+`,
+}
+
+func newHistoryServer(t *testing.T, shards map[string]string) (*Server, string) {
+	t.Helper()
+	bucketURL := "file://" + t.TempDir()
+	for key, content := range shards {
+		if err := data.WriteToBlobStore(context.Background(), bucketURL, key, []byte(content)); err != nil {
+			t.Fatalf("WriteToBlobStore: %v", err)
+		}
+	}
+	srv, err := New(Options{
+		Logger:   zap.NewNop().Sugar(),
+		Checks:   checker.CheckNameToFnMap{},
+		LogLevel: zapcore.InfoLevel,
+		History:  &HistoryOptions{BucketURL: bucketURL},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for getHistory(srv, "/api/v1/history/github.com/ossf/scorecard/scores", "").Code ==
+		http.StatusServiceUnavailable {
+		if time.Now().After(deadline) {
+			t.Fatal("history was not indexed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return srv, bucketURL
+}
+
+func getHistory(srv *Server, target, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func TestHistoryScores(t *testing.T) {
+	t.Parallel()
+	srv, _ := newHistoryServer(t, historyShards)
+	tests := []struct {
+		name      string
+		target    string
+		wantCode  int
+		wantClass string
+		want      ScoreSeries
+	}{
+		{
+			name:     "all checks",
+			target:   "/api/v1/history/github.com/ossf/scorecard/scores",
+			wantCode: http.StatusOK,
+			want: ScoreSeries{
+				Repo: "github.com/ossf/scorecard",
+				Points: []ScorePoint{
+					{
+						Time:   time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC),
+						Score:  float(0),
+						Checks: map[string]int{"Code-Review": 0, "Signed-Releases": -1},
+					},
+					{
+						Time:   time.Date(2021, 8, 8, 0, 0, 0, 0, time.UTC),
+						Score:  float(5),
+						Checks: map[string]int{"Code-Review": 10, "Signed-Releases": 0},
+					},
+				},
+			},
+		},
+		{
+			name:     "selected check of canonicalised repo",
+			target:   "/api/v1/history/github.com/OSSF/scorecard.git/scores?checks=code-review",
+			wantCode: http.StatusOK,
+			want: ScoreSeries{
+				Repo: "github.com/ossf/scorecard",
+				Points: []ScorePoint{
+					{
+						Time:   time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC),
+						Score:  float(0),
+						Checks: map[string]int{"Code-Review": 0},
+					},
+					{
+						Time:   time.Date(2021, 8, 8, 0, 0, 0, 0, time.UTC),
+						Score:  float(5),
+						Checks: map[string]int{"Code-Review": 10},
+					},
+				},
+			},
+		},
+		{
+			name:      "repo without results",
+			target:    "/api/v1/history/github.com/ossf/unknown/scores",
+			wantCode:  http.StatusNotFound,
+			wantClass: "ErrNotFound",
+		},
+		{
+			name:      "unknown route",
+			target:    "/api/v1/history/github.com/ossf/scorecard",
+			wantCode:  http.StatusNotFound,
+			wantClass: "ErrRouteNotFound",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Re-initializing variable so it is not changed while executing the closure below
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rec := getHistory(srv, tt.target, "")
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if tt.wantClass != "" {
+				var body errorBody
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatalf("json.Unmarshal: %v", err)
+				}
+				if body.Error.Class != tt.wantClass {
+					t.Errorf("class = %s, want %s", body.Error.Class, tt.wantClass)
+				}
+				return
+			}
+			var got ScoreSeries
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("series mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHistoryLatest(t *testing.T) {
+	t.Parallel()
+	srv, bucketURL := newHistoryServer(t, historyShards)
+
+	rec := getHistory(srv, "/api/v1/history/github.com/ossf/scorecard/latest?details=true", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), `"Date":"2021-08-08"`) || !strings.Contains(rec.Body.String(), `"new"`) {
+		t.Errorf("body = %s, want the result of 2021-08-08 with details", rec.Body)
+	}
+
+	// Shards written after the server started are served once indexed.
+	if err := data.WriteToBlobStore(context.Background(), bucketURL, "2021.08.15/000000/shard-00003",
+		[]byte(`{"Repo":"github.com/ossf/scorecard","Date":"2021-08-15","Checks":[]}`+"\n")); err != nil {
+		t.Fatalf("WriteToBlobStore: %v", err)
+	}
+	if err := srv.history.refresh(context.Background()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	rec = getHistory(srv, "/api/v1/history/github.com/ossf/scorecard/latest", "")
+	if !strings.Contains(rec.Body.String(), `"Date":"2021-08-15"`) {
+		t.Errorf("body = %s, want the result of 2021-08-15", rec.Body)
+	}
+
+	rec = getHistory(srv, "/api/v1/history/github.com/ossf/scorecard/scores", "text/html")
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, contentTypeHTML) {
+		t.Errorf("Content-Type = %s, want %s", got, contentTypeHTML)
+	}
+	if !strings.Contains(rec.Body.String(), "<td>2021-08-15 00:00</td>") {
+		t.Errorf("body = %s, want a row for 2021-08-15", rec.Body)
+	}
+}
+
+func TestHistoryBucketError(t *testing.T) {
+	t.Parallel()
+	srv, err := New(Options{
+		Logger:   zap.NewNop().Sugar(),
+		Checks:   checker.CheckNameToFnMap{},
+		LogLevel: zapcore.InfoLevel,
+		History:  &HistoryOptions{BucketURL: "file://" + t.TempDir() + "/missing"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	if err := srv.history.refresh(context.Background()); err == nil {
+		t.Fatal("refresh of a missing bucket succeeded")
+	}
+	rec := getHistory(srv, "/api/v1/history/github.com/ossf/scorecard/scores", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusServiceUnavailable, rec.Body)
+	}
+}
@@ -0,0 +1,428 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ossf/scorecard/v2/checker"
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+// JobState is the state of a scan job.
+type JobState string
+
+// Scan job states.
+const (
+	JobQueued    JobState = "queued"
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+)
+
+const (
+	defaultJobWorkers   = 2
+	defaultJobQueueSize = 100
+	defaultJobRetention = 7 * 24 * time.Hour
+
+	jobIDLen = 16
+)
+
+var errQueueFull = errors.New("queue full")
+
+// JobOptions configure asynchronous scan jobs.
+type JobOptions struct {
+	// Dir, if set, is where jobs are persisted, so that they survive restarts.
+	// Jobs which were queued or running when the server stopped are run again.
+	Dir string
+	// Workers is the number of jobs which run concurrently.
+	Workers int
+	// QueueSize is the number of jobs which may wait for a worker. Jobs submitted
+	// while the queue is full are rejected.
+	QueueSize int
+	// Retention is how long finished jobs are kept.
+	Retention time.Duration
+}
+
+// Job is a scan job, as returned by the API and persisted.
+type Job struct {
+	ID       string          `json:"id"`
+	Repo     string          `json:"repo"`
+	Details  bool            `json:"details"`
+	State    JobState        `json:"state"`
+	Progress []CheckProgress `json:"progress"`
+	Created  time.Time       `json:"created"`
+	Started  *time.Time      `json:"started,omitempty"`
+	Finished *time.Time      `json:"finished,omitempty"`
+	Error    *httpError      `json:"error,omitempty"`
+	// Result is the JSON result, as returned by the repos endpoint.
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// CheckProgress tells whether a check of a job has started and finished.
+type CheckProgress struct {
+	Name     string     `json:"name"`
+	Done     bool       `json:"done"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+}
+
+// scanRequest is the body of POST /api/v1/scans.
+type scanRequest struct {
+	Repo    string   `json:"repo"`
+	Checks  []string `json:"checks"`
+	Details bool     `json:"details"`
+}
+
+// jobQueue runs scan jobs on a bounded pool of workers.
+type jobQueue struct {
+	server *Server
+	opts   JobOptions
+	queue  chan string
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// newJobQueue loads the jobs persisted in opts.Dir and starts the workers.
+func newJobQueue(server *Server, opts JobOptions) (*jobQueue, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = defaultJobWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultJobQueueSize
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = defaultJobRetention
+	}
+	q := &jobQueue{
+		server: server,
+		opts:   opts,
+		jobs:   make(map[string]*Job),
+	}
+	pending, err := q.load()
+	if err != nil {
+		return nil, err
+	}
+	q.queue = make(chan string, opts.QueueSize+len(pending))
+	for _, id := range pending {
+		q.queue <- id
+	}
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	for i := 0; i < opts.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q, nil
+}
+
+// load reads the persisted jobs, and returns the IDs of the jobs which have not
+// finished, oldest first.
+func (q *jobQueue) load() ([]string, error) {
+	if q.opts.Dir == "" {
+		return nil, nil
+	}
+	// nolint: gomnd
+	if err := os.MkdirAll(q.opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating job directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(q.opts.Dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing jobs: %w", err)
+	}
+	var pending []*Job
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading job: %w", err)
+		}
+		job := &Job{}
+		if err := json.Unmarshal(data, job); err != nil {
+			q.server.opts.Logger.Warnf("skipping invalid job %s: %v", path, err)
+			continue
+		}
+		q.jobs[job.ID] = job
+		if job.State == JobQueued || job.State == JobRunning {
+			job.State, job.Started = JobQueued, nil
+			for i := range job.Progress {
+				job.Progress[i] = CheckProgress{Name: job.Progress[i].Name}
+			}
+			pending = append(pending, job)
+		}
+	}
+	q.prune()
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Created.Before(pending[j].Created)
+	})
+	ids := make([]string, len(pending))
+	for i, job := range pending {
+		ids[i] = job.ID
+	}
+	return ids, nil
+}
+
+// submit queues a job for the checks on the repo.
+func (q *jobQueue) submit(params *repoParams) (Job, error) {
+	return q.enqueue(params, false)
+}
+
+// submitUnlessQueued queues a job for the checks on the repo, unless an identical
+// job is already waiting for a worker, in which case that job is returned.
+func (q *jobQueue) submitUnlessQueued(params *repoParams) (Job, error) {
+	return q.enqueue(params, true)
+}
+
+func (q *jobQueue) enqueue(params *repoParams, dedupe bool) (Job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+	job := &Job{
+		ID:      id,
+		Repo:    params.repo.URL(),
+		Details: params.details,
+		State:   JobQueued,
+		Created: time.Now().UTC(),
+	}
+	for name := range params.checks {
+		job.Progress = append(job.Progress, CheckProgress{Name: name})
+	}
+	sort.Slice(job.Progress, func(i, j int) bool {
+		return job.Progress[i].Name < job.Progress[j].Name
+	})
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.prune()
+	if dedupe {
+		for _, queued := range q.jobs {
+			if queued.State == JobQueued && sameScan(queued, job) {
+				return *queued, nil
+			}
+		}
+	}
+	select {
+	case q.queue <- id:
+	default:
+		return Job{}, newError(http.StatusServiceUnavailable, errQueueFull,
+			fmt.Sprintf("%d scans are already queued", q.opts.QueueSize))
+	}
+	q.jobs[id] = job
+	q.save(job)
+	return *job, nil
+}
+
+// get returns a copy of the job with the ID.
+func (q *jobQueue) get(id string) (Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	ret := *job
+	ret.Progress = append([]CheckProgress(nil), job.Progress...)
+	return ret, true
+}
+
+// close stops the workers. Running jobs are canceled, and are run again on restart
+// if jobs are persisted.
+func (q *jobQueue) close() {
+	q.cancel()
+	q.wg.Wait()
+}
+
+func (q *jobQueue) work() {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case id := <-q.queue:
+			q.run(id)
+		}
+	}
+}
+
+// run runs the job with the ID and records its result.
+func (q *jobQueue) run(id string) {
+	q.mu.Lock()
+	job, ok := q.jobs[id]
+	if !ok {
+		q.mu.Unlock()
+		return
+	}
+	started := time.Now().UTC()
+	job.State, job.Started = JobRunning, &started
+	q.save(job)
+	q.mu.Unlock()
+
+	result, err := q.score(job)
+	if q.ctx.Err() != nil {
+		// The server is stopping. The job is left running so that it is run again.
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	finished := time.Now().UTC()
+	job.Finished = &finished
+	if err != nil {
+		q.server.opts.Logger.Error(err)
+		job.State, job.Error = JobFailed, toHTTPError(err)
+	} else {
+		job.State, job.Result = JobSucceeded, result
+	}
+	q.save(job)
+}
+
+// score runs the job's checks, recording when each check starts and finishes, and returns
+// the JSON result. The result is also stored in the result cache.
+func (q *jobQueue) score(job *Job) (json.RawMessage, error) {
+	var repo repos.RepoURL
+	if err := repo.Set(job.Repo); err != nil {
+		//nolint:wrapcheck
+		return nil, err
+	}
+	checks := checker.CheckNameToFnMap{}
+	progress := make(map[string]*CheckProgress, len(job.Progress))
+	for i := range job.Progress {
+		name := job.Progress[i].Name
+		fn, ok := q.server.opts.Checks[name]
+		if !ok {
+			return nil, newError(http.StatusBadRequest, errInvalidRequest, fmt.Sprintf("invalid check: %s", name))
+		}
+		checks[name] = fn
+		progress[name] = &job.Progress[i]
+	}
+	onEvent := func(event pkg.CheckEvent) {
+		check, ok := progress[event.Check]
+		if !ok {
+			return
+		}
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		started := event.Start.UTC()
+		check.Started = &started
+		if event.Type == pkg.CheckFinished {
+			finished := event.Start.Add(event.Duration).UTC()
+			check.Done, check.Finished = true, &finished
+		}
+		q.save(job)
+	}
+	// The result is keyed by the HEAD commit before the scan, so that a later commit
+	// is never served this result.
+	var cacheKey string
+	if q.server.opts.Cache != nil {
+		key, err := q.server.cacheKey(q.ctx, &repoParams{repo: repo, checks: checks})
+		if err != nil {
+			q.server.opts.Logger.Warnf("not caching the result of job %s: %v", job.ID, err)
+		}
+		cacheKey = key
+	}
+	result, err := q.server.scoreStream(q.ctx, repo, checks, onEvent)
+	if err != nil {
+		return nil, err
+	}
+	if cacheKey != "" {
+		q.server.opts.Cache.Set(cacheKey, result)
+	}
+	sort.Slice(result.Checks, func(i, j int) bool {
+		return result.Checks[i].Name < result.Checks[j].Name
+	})
+	var buf bytes.Buffer
+	if err := result.AsJSON(job.Details, q.server.opts.LogLevel, &buf); err != nil {
+		//nolint:wrapcheck
+		return nil, err
+	}
+	return json.RawMessage(bytes.TrimSpace(buf.Bytes())), nil
+}
+
+// sameScan reports whether the jobs run the same checks on the same repo.
+func sameScan(a, b *Job) bool {
+	if a.Repo != b.Repo || a.Details != b.Details || len(a.Progress) != len(b.Progress) {
+		return false
+	}
+	for i := range a.Progress {
+		if a.Progress[i].Name != b.Progress[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// prune drops finished jobs older than the retention period. q.mu must be held.
+func (q *jobQueue) prune() {
+	for id, job := range q.jobs {
+		if job.Finished != nil && time.Since(*job.Finished) > q.opts.Retention {
+			delete(q.jobs, id)
+			if q.opts.Dir != "" {
+				if err := os.Remove(q.path(id)); err != nil && !os.IsNotExist(err) {
+					q.server.opts.Logger.Warn(err)
+				}
+			}
+		}
+	}
+}
+
+// save persists the job. q.mu must be held.
+func (q *jobQueue) save(job *Job) {
+	if q.opts.Dir == "" {
+		return
+	}
+	data, err := json.Marshal(job)
+	if err == nil {
+		// Write to a temporary file first so that jobs are never left half-written.
+		tmp := q.path(job.ID) + ".tmp"
+		// nolint: gomnd
+		if err = ioutil.WriteFile(tmp, data, 0o644); err == nil {
+			err = os.Rename(tmp, q.path(job.ID))
+		}
+	}
+	if err != nil {
+		q.server.opts.Logger.Warnf("error saving job %s: %v", job.ID, err)
+	}
+}
+
+func (q *jobQueue) path(id string) string {
+	return filepath.Join(q.opts.Dir, id+".json")
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, jobIDLen)
+	if _, err := rand.Read(b); err != nil {
+		//nolint:wrapcheck
+		return "", sce.Create(sce.ErrScorecardInternal, fmt.Sprintf("rand.Read: %v", err))
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeHTML = "text/html"
+)
+
+// formats are the content types results are served in, in order of preference.
+var formats = []string{contentTypeJSON, contentTypeHTML}
+
+// negotiateFormat returns the content type of formats which the request's Accept
+// header rates highest. Each format is rated by the most specific media range which
+// matches it, and JSON is returned if there is no Accept header.
+func negotiateFormat(r *http.Request) (string, error) {
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return contentTypeJSON, nil
+	}
+	ranges := parseAccept(accept)
+	best, bestQuality := "", 0.0
+	for _, format := range formats {
+		if q := quality(ranges, format); q > bestQuality {
+			best, bestQuality = format, q
+		}
+	}
+	if best == "" {
+		return "", newError(http.StatusNotAcceptable, errNotAcceptable,
+			"supported content types: "+strings.Join(formats, ", "))
+	}
+	return best, nil
+}
+
+// mediaRange is a media range of an Accept header, e.g. `text/*;q=0.5`.
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// quality returns the quality of the most specific media range matching contentType.
+func quality(ranges []mediaRange, contentType string) float64 {
+	q, specificity := 0.0, -1
+	for _, mr := range ranges {
+		s := mr.specificity(contentType)
+		if s > specificity {
+			q, specificity = mr.q, s
+		}
+	}
+	return q
+}
+
+// specificity returns how specifically the range matches contentType: 2 for the type
+// itself, 1 for `type/*`, 0 for `*/*` and -1 if it does not match.
+func (mr mediaRange) specificity(contentType string) int {
+	switch {
+	case mr.mediaType == contentType:
+		return 2
+	case mr.mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(mr.mediaType, "/*") &&
+		strings.HasPrefix(contentType, strings.TrimSuffix(mr.mediaType, "*")):
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ossf/scorecard/v2/checker"
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+// repoParams are the parameters of a request for the results of a repo.
+type repoParams struct {
+	repo    repos.RepoURL
+	checks  checker.CheckNameToFnMap
+	details bool
+	// refresh skips the cache.
+	refresh bool
+}
+
+// parseRepoPath returns the repo named by the `{host}/{owner}/{repo}` path.
+func parseRepoPath(path string) (string, error) {
+	const length = 3
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if len(segments) != length {
+		return "", newError(http.StatusNotFound, errRouteNotFound,
+			fmt.Sprintf("expected %srepos/{host}/{owner}/{repo}", APIPrefix))
+	}
+	for _, segment := range segments {
+		if segment == "" {
+			return "", newError(http.StatusNotFound, errRouteNotFound,
+				fmt.Sprintf("expected %srepos/{host}/{owner}/{repo}", APIPrefix))
+		}
+	}
+	return path, nil
+}
+
+// parseParams parses the repo and the `checks`, `details` and `refresh` query parameters.
+func (s *Server) parseParams(r *http.Request, repoParam string) (repoParams, error) {
+	query := r.URL.Query()
+	var details, refresh bool
+	for name, v := range map[string]*bool{"details": &details, "refresh": &refresh} {
+		if raw := query.Get(name); raw != "" {
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				return repoParams{}, newError(http.StatusBadRequest, errInvalidRequest,
+					fmt.Sprintf("invalid %s query parameter: %q", name, raw))
+			}
+			*v = b
+		}
+	}
+
+	params, err := s.makeParams(repoParam, queryList(r, "checks"))
+	params.details, params.refresh = details, refresh
+	return params, err
+}
+
+// queryList returns the values of the query parameter, which may be comma-separated
+// or repeated.
+func queryList(r *http.Request, name string) []string {
+	var ret []string
+	for _, v := range r.URL.Query()[name] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				ret = append(ret, item)
+			}
+		}
+	}
+	return ret
+}
+
+// makeParams validates the repo and selects the checks with the names.
+func (s *Server) makeParams(repoParam string, names []string) (repoParams, error) {
+	var params repoParams
+	if err := params.repo.Set(repoParam); err != nil {
+		return params, newError(http.StatusBadRequest, errInvalidRequest, err.Error())
+	}
+	if err := params.repo.ValidGitHubURL(); err != nil {
+		if errors.Is(err, sce.ErrUnsupportedHost) {
+			return params, newError(http.StatusBadRequest, err, err.Error())
+		}
+		return params, newError(http.StatusBadRequest, errInvalidRequest, err.Error())
+	}
+	checks, err := s.selectChecks(names)
+	if err != nil {
+		return params, err
+	}
+	params.checks = checks
+	return params, nil
+}
+
+// selectChecks returns the checks with the names, matched case-insensitively, or all
+// checks if there are no names.
+func (s *Server) selectChecks(names []string) (checker.CheckNameToFnMap, error) {
+	if len(names) == 0 {
+		return s.opts.Checks, nil
+	}
+	ret := checker.CheckNameToFnMap{}
+	for _, name := range names {
+		found := false
+		for key, fn := range s.opts.Checks {
+			if strings.EqualFold(key, name) {
+				ret[key] = fn
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, newError(http.StatusBadRequest, errInvalidRequest, fmt.Sprintf("invalid check: %s", name))
+		}
+	}
+	return ret, nil
+}
+
+// resultPage is the data of resultTemplate.
+type resultPage struct {
+	*pkg.ScorecardResult
+	ShowDetails bool
+}
+
+// writeResult writes the result in the content type format.
+func (s *Server) writeResult(w http.ResponseWriter, r *http.Request, format string,
+	result *pkg.ScorecardResult, details bool) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case contentTypeHTML:
+		err = s.page.Execute(&buf, resultPage{ScorecardResult: result, ShowDetails: details})
+	default:
+		err = result.AsJSON(details, s.opts.LogLevel, &buf)
+	}
+	if err != nil {
+		s.opts.Logger.Error(err)
+		s.writeError(w, r, newError(http.StatusInternalServerError, sce.ErrScorecardInternal, err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", format+"; charset=utf-8")
+	w.Header().Set("Vary", "Accept")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		s.opts.Logger.Warn(err)
+	}
+}
+
+const resultTemplate = `
+<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="UTF-8">
+		<title>Scorecard Results for: {{.Repo}}</title>
+	</head>
+	<body>
+		{{$details := .ShowDetails}}
+		{{range .Checks}}
+			<div>
+				<p>{{ .Name }}: {{ .Pass }}</p>
+				{{if $details}}
+					<ul>
+						{{range .Details}}<li>{{.}}</li>{{end}}
+					</ul>
+				{{end}}
+			</div>
+		{{end}}
+	</body>
+</html>`
@@ -0,0 +1,127 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+const (
+	// xCache is set to HIT on results served from the cache, and to MISS otherwise.
+	xCache = "X-Scorecard-Cache"
+
+	defaultScanTimeout = 10 * time.Minute
+)
+
+// scan returns the result of the requested checks, from the cache unless a refresh was
+// requested. Concurrent scans with the same key are coalesced into one.
+func (s *Server) scan(ctx context.Context, params *repoParams) (pkg.ScorecardResult, bool, error) {
+	key, err := s.cacheKey(ctx, params)
+	if err != nil {
+		return pkg.ScorecardResult{}, false, err
+	}
+	if s.opts.Cache != nil && !params.refresh {
+		if result, ok := s.opts.Cache.Get(key); ok {
+			return result, true, nil
+		}
+	}
+	v, err, _ := s.scans.Do(key, func() (interface{}, error) {
+		// The scan is shared by all the requests waiting for it, so it is not
+		// canceled with any one of them, but it times out.
+		ctx, cancel := context.WithTimeout(context.Background(), s.opts.ScanTimeout)
+		defer cancel()
+		result, err := s.opts.Score(ctx, params.repo, params.checks)
+		if err != nil {
+			return nil, err
+		}
+		if s.opts.Cache != nil {
+			s.opts.Cache.Set(key, result)
+		}
+		return result, nil
+	})
+	if err != nil {
+		//nolint:wrapcheck
+		return pkg.ScorecardResult{}, false, err
+	}
+	return v.(pkg.ScorecardResult), false, nil
+}
+
+// cacheKey returns `<repo>:<sorted checks>@<HEAD commit>`.
+func (s *Server) cacheKey(ctx context.Context, params *repoParams) (string, error) {
+	var head string
+	if s.opts.Head != nil {
+		var err error
+		if head, err = s.opts.Head(ctx, params.repo); err != nil {
+			//nolint:wrapcheck
+			return "", err
+		}
+	}
+	return latestKey(params) + "@" + head, nil
+}
+
+// latestKey returns `<repo>:<sorted checks>`, the cache key of the latest result of
+// the repo, see ResultCache.Latest.
+func latestKey(params *repoParams) string {
+	checks := make([]string, 0, len(params.checks))
+	for name := range params.checks {
+		checks = append(checks, name)
+	}
+	sort.Strings(checks)
+	return fmt.Sprintf("%s:%s", params.repo.URL(), strings.Join(checks, ","))
+}
+
+// scoreStream runs the checks with ScoreStream if it is set. Otherwise, it runs them
+// with Score, reporting each check when its function returns. Calls to onEvent are
+// serialized. The scan times out after the scan timeout.
+func (s *Server) scoreStream(ctx context.Context, repo repos.RepoURL, checks checker.CheckNameToFnMap,
+	onEvent pkg.CheckEventFunc) (pkg.ScorecardResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.ScanTimeout)
+	defer cancel()
+	if s.opts.ScoreStream != nil {
+		return s.opts.ScoreStream(ctx, repo, checks, onEvent)
+	}
+	var mu sync.Mutex
+	wrapped := checker.CheckNameToFnMap{}
+	for name, fn := range checks {
+		name, fn := name, fn
+		wrapped[name] = func(c *checker.CheckRequest) checker.CheckResult {
+			start := time.Now()
+			mu.Lock()
+			onEvent(pkg.CheckEvent{Type: pkg.CheckStarted, Check: name, Start: start})
+			mu.Unlock()
+			result := fn(c)
+			mu.Lock()
+			defer mu.Unlock()
+			onEvent(pkg.CheckEvent{
+				Type:     pkg.CheckFinished,
+				Check:    name,
+				Start:    start,
+				Duration: time.Since(start),
+				Result:   result,
+			})
+			return result
+		}
+	}
+	return s.opts.Score(ctx, repo, wrapped)
+}
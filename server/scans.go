@@ -0,0 +1,81 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	sce "github.com/ossf/scorecard/v2/errors"
+)
+
+// maxScanRequestSize limits the size of POST /api/v1/scans bodies.
+const maxScanRequestSize = 1 << 16
+
+// handleScans queues a scan job for the repo and checks in the request body.
+func (s *Server) handleScans(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	var req scanRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxScanRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		s.writeError(w, r, newError(http.StatusBadRequest, errInvalidRequest, fmt.Sprintf("invalid body: %v", err)))
+		return
+	}
+	if req.Repo == "" {
+		s.writeError(w, r, newError(http.StatusBadRequest, errInvalidRequest, "missing repo"))
+		return
+	}
+	params, err := s.makeParams(req.Repo, req.Checks)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	params.details = req.Details
+	job, err := s.jobs.submit(&params)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", APIPrefix+"scans/"+job.ID)
+	s.writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleScan returns the state, progress and result of a scan job.
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethods(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, APIPrefix+"scans/")
+	job, ok := s.jobs.get(id)
+	if !ok {
+		s.writeError(w, r, newError(http.StatusNotFound, sce.ErrNotFound, fmt.Sprintf("scan %q not found", id)))
+		return
+	}
+	s.writeJSON(w, http.StatusOK, job)
+}
+
+// writeJSON writes v as a JSON response.
+func (s *Server) writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", contentTypeJSON+"; charset=utf-8")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.opts.Logger.Warn(err)
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+// blockingScore returns a ScoreFunc which runs the checks once release is closed,
+// or fails if the scan is canceled first.
+func blockingScore(release <-chan struct{}) ScoreFunc {
+	return func(ctx context.Context, repo repos.RepoURL,
+		checks checker.CheckNameToFnMap) (pkg.ScorecardResult, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return pkg.ScorecardResult{}, ctx.Err()
+		}
+		result := pkg.ScorecardResult{Repo: repo.URL()}
+		for _, fn := range checks {
+			result.Checks = append(result.Checks, fn(&checker.CheckRequest{}))
+		}
+		return result, nil
+	}
+}
+
+func newJobServer(t *testing.T, score ScoreFunc, jobs JobOptions) *Server {
+	t.Helper()
+	check := func(name string) checker.CheckFn {
+		return func(c *checker.CheckRequest) checker.CheckResult {
+			return checker.CheckResult{Name: name, Pass: true}
+		}
+	}
+	srv, err := New(Options{
+		Logger: zap.NewNop().Sugar(),
+		Score:  score,
+		Checks: checker.CheckNameToFnMap{"Code-Review": check("Code-Review"), "Fuzzing": check("Fuzzing")},
+		Jobs:   &jobs,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func postScan(t *testing.T, srv *Server, body string) (*httptest.ResponseRecorder, Job) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/scans", strings.NewReader(body)))
+	var job Job
+	if rec.Code == http.StatusAccepted {
+		if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+			t.Fatalf("json.Unmarshal: %v", err)
+		}
+	}
+	return rec, job
+}
+
+func getScan(t *testing.T, srv *Server, id string) (int, Job) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/scans/"+id, nil))
+	var job Job
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+			t.Fatalf("json.Unmarshal: %v", err)
+		}
+	}
+	return rec.Code, job
+}
+
+// waitForState polls the job until it is in the state.
+func waitForState(t *testing.T, srv *Server, id string, state JobState) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, job := getScan(t, srv, id)
+		if job.State == state {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, want %s", id, job.State, state)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestScans(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	srv := newJobServer(t, blockingScore(release), JobOptions{})
+
+	rec, job := postScan(t, srv, `{"repo": "github.com/ossf/scorecard", "checks": ["code-review"]}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusAccepted, rec.Body)
+	}
+	if got, want := rec.Header().Get("Location"), "/api/v1/scans/"+job.ID; got != want {
+		t.Errorf("Location = %s, want %s", got, want)
+	}
+	job = waitForState(t, srv, job.ID, JobRunning)
+	if len(job.Progress) != 1 || job.Progress[0].Name != "Code-Review" || job.Progress[0].Done {
+		t.Errorf("unexpected progress of running job: %+v", job.Progress)
+	}
+
+	close(release)
+	job = waitForState(t, srv, job.ID, JobSucceeded)
+	if !job.Progress[0].Done {
+		t.Errorf("unexpected progress of finished job: %+v", job.Progress)
+	}
+	var result pkg.ScorecardResult
+	if err := json.Unmarshal(job.Result, &result); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if result.Repo != "github.com/ossf/scorecard" || len(result.Checks) != 1 {
+		t.Errorf("unexpected result: %s", job.Result)
+	}
+
+	if code, _ := getScan(t, srv, "unknown"); code != http.StatusNotFound {
+		t.Errorf("status of unknown job = %d, want %d", code, http.StatusNotFound)
+	}
+	for _, body := range []string{
+		`{"repo": "github.com/ossf/scorecard", "checks": ["Unknown"]}`,
+		`{"checks": ["Fuzzing"]}`,
+		`{"repository": "github.com/ossf/scorecard"}`,
+	} {
+		if rec, _ := postScan(t, srv, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("status of %s = %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestScansQueueFull(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	srv := newJobServer(t, blockingScore(release), JobOptions{Workers: 1, QueueSize: 1})
+
+	const body = `{"repo": "github.com/ossf/scorecard"}`
+	_, running := postScan(t, srv, body)
+	waitForState(t, srv, running.ID, JobRunning)
+	if rec, _ := postScan(t, srv, body); rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	rec, _ := postScan(t, srv, body)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Errorf("missing Retry-After header")
+	}
+	if !strings.Contains(rec.Body.String(), "ErrQueueFull") {
+		t.Errorf("unexpected body: %s", rec.Body)
+	}
+}
+
+func TestScansPersisted(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	blocked := make(chan struct{})
+	srv := newJobServer(t, blockingScore(blocked), JobOptions{Dir: dir})
+	_, job := postScan(t, srv, `{"repo": "github.com/ossf/scorecard", "details": true}`)
+	waitForState(t, srv, job.ID, JobRunning)
+	// Stopping the server cancels the running job.
+	srv.Close()
+	// The progress of checks which ran before the restart is reset.
+	path := filepath.Join(dir, job.ID+".json")
+	saved, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if err := json.Unmarshal(saved, &job); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	now := time.Now()
+	job.Progress[0] = CheckProgress{Name: job.Progress[0].Name, Done: true, Started: &now, Finished: &now}
+	if saved, err = json.Marshal(job); err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	// nolint: gomnd
+	if err := ioutil.WriteFile(path, saved, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	restarting := newJobServer(t, blockingScore(blocked), JobOptions{Dir: dir})
+	job = waitForState(t, restarting, job.ID, JobRunning)
+	for _, progress := range job.Progress {
+		if progress.Done || progress.Started != nil || progress.Finished != nil {
+			t.Errorf("unexpected progress of restarted job: %+v", progress)
+		}
+	}
+	restarting.Close()
+
+	release := make(chan struct{})
+	close(release)
+	restarted := newJobServer(t, blockingScore(release), JobOptions{Dir: dir})
+	job = waitForState(t, restarted, job.ID, JobSucceeded)
+	if !job.Details || len(job.Progress) != 2 {
+		t.Errorf("unexpected restarted job: %+v", job)
+	}
+
+	// Finished jobs are loaded as they are.
+	loaded := newJobServer(t, blockingScore(blocked), JobOptions{Dir: dir})
+	if _, got := getScan(t, loaded, job.ID); got.State != JobSucceeded || string(got.Result) != string(job.Result) {
+		t.Errorf("unexpected loaded job: %+v", got)
+	}
+}
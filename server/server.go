@@ -0,0 +1,238 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server serves Scorecard results over HTTP.
+package server
+
+import (
+	"context"
+	_ "embed" // Needed for the OpenAPI description.
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+// APIPrefix is the path prefix of version 1 of the REST API.
+const APIPrefix = "/api/v1/"
+
+var errWebhookWithoutJobs = errors.New("the webhook receiver requires scan jobs")
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// ScoreFunc runs checks on a repo.
+type ScoreFunc func(ctx context.Context, repo repos.RepoURL, checks checker.CheckNameToFnMap) (pkg.ScorecardResult, error)
+
+// ScoreStreamFunc runs checks on a repo like a ScoreFunc, and calls onEvent as each
+// check starts and finishes.
+type ScoreStreamFunc func(ctx context.Context, repo repos.RepoURL, checks checker.CheckNameToFnMap,
+	onEvent pkg.CheckEventFunc) (pkg.ScorecardResult, error)
+
+// HeadFunc returns the commit at the HEAD of a repo's default branch.
+type HeadFunc func(ctx context.Context, repo repos.RepoURL) (string, error)
+
+// Options configure a Server.
+type Options struct {
+	Logger *zap.SugaredLogger
+	// Score runs the checks requested from the API.
+	Score ScoreFunc
+	// ScoreStream, if set, runs the checks of scan jobs and gRPC streams, which report
+	// each check as it progresses. Otherwise they run with Score, and each check is
+	// reported when its function returns.
+	ScoreStream ScoreStreamFunc
+	// Checks are the checks which may be requested. All of them run unless the
+	// request selects some with the `checks` query parameter.
+	Checks checker.CheckNameToFnMap
+	// LogLevel is the minimum level of the check details which are returned.
+	LogLevel zapcore.Level
+	// Cache, if set, caches results by repo, HEAD commit and checks.
+	Cache *ResultCache
+	// Head returns the HEAD commit which cached results are keyed by. If it is not
+	// set, cached results are only keyed by repo and checks.
+	Head HeadFunc
+	// Jobs, if set, enables asynchronous scan jobs.
+	Jobs *JobOptions
+	// WebhookSecret, if set, enables the GitHub webhook receiver, which rescores repos
+	// in scan jobs. Deliveries must be signed with the secret.
+	WebhookSecret string
+	// History, if set, serves the results which the cron job wrote to a bucket.
+	History *HistoryOptions
+	// APIKeys, if set, are required to use the API, except for badges, webhook
+	// deliveries and the OpenAPI description. Each key is limited to its quota.
+	APIKeys []APIKey
+	// ScanTimeout bounds how long a scan may run, so that a hung request to GitHub
+	// does not block the requests waiting for the scan forever. Defaults to 10 minutes.
+	ScanTimeout time.Duration
+}
+
+// Server is the HTTP handler of the serve command.
+type Server struct {
+	opts       Options
+	mux        *http.ServeMux
+	page       *template.Template
+	scoresPage *template.Template
+	// scans coalesces concurrent scans with the same cache key.
+	scans   singleflight.Group
+	jobs    *jobQueue
+	history *history
+	auth    *authenticator
+}
+
+// New returns a Server with its routes registered. If jobs are enabled, their
+// workers are started, and if the history is enabled, it is indexed in the
+// background. Close must be called to stop them.
+func New(opts Options) (*Server, error) {
+	page, err := template.New("webpage").Parse(resultTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing result template: %w", err)
+	}
+	scoresPage, err := template.New("scores").Parse(scoresTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing scores template: %w", err)
+	}
+	if opts.ScanTimeout <= 0 {
+		opts.ScanTimeout = defaultScanTimeout
+	}
+	s := &Server{
+		opts:       opts,
+		mux:        http.NewServeMux(),
+		page:       page,
+		scoresPage: scoresPage,
+	}
+	s.mux.HandleFunc(APIPrefix+"repos/", s.handleRepo)
+	if opts.Jobs != nil {
+		if s.jobs, err = newJobQueue(s, *opts.Jobs); err != nil {
+			return nil, err
+		}
+		s.mux.HandleFunc(APIPrefix+"scans", s.handleScans)
+		s.mux.HandleFunc(APIPrefix+"scans/", s.handleScan)
+	}
+	if opts.WebhookSecret != "" {
+		if s.jobs == nil {
+			return nil, errWebhookWithoutJobs
+		}
+		s.mux.HandleFunc(APIPrefix+"webhooks/github", s.handleGitHubWebhook)
+	}
+	if len(opts.APIKeys) > 0 {
+		s.auth = newAuthenticator(opts.APIKeys)
+	}
+	if opts.History != nil {
+		s.history = newHistory(s, *opts.History)
+		s.mux.HandleFunc(APIPrefix+"history/", s.handleHistory)
+	}
+	s.mux.HandleFunc(APIPrefix+"openapi.yaml", s.handleOpenAPI)
+	s.mux.HandleFunc(BadgePrefix, s.handleBadge)
+	s.mux.HandleFunc("/", s.handleRoot)
+	return s, nil
+}
+
+// Close stops the workers of scan jobs and the indexing of the history. Running
+// jobs are run again after a restart if jobs are persisted.
+func (s *Server) Close() {
+	if s.jobs != nil {
+		s.jobs.close()
+	}
+	if s.history != nil {
+		s.history.close()
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.auth != nil && !authPublic(r.URL.Path) {
+		if err := s.auth.authorize(r); err != nil {
+			s.writeError(w, r, err)
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethods(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	if _, err := w.Write(openAPISpec); err != nil {
+		s.opts.Logger.Warn(err)
+	}
+}
+
+// handleRoot serves the results for the `repo` query parameter, which predates the
+// REST API and is kept for compatibility.
+func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		s.writeError(w, r, newError(http.StatusNotFound, errRouteNotFound, r.URL.Path))
+		return
+	}
+	if !s.allowMethods(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+	repoParam := r.URL.Query().Get("repo")
+	if repoParam == "" {
+		s.writeError(w, r, newError(http.StatusBadRequest, errInvalidRequest, "missing repo query parameter"))
+		return
+	}
+	s.serveRepo(w, r, repoParam)
+}
+
+func (s *Server) handleRepo(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethods(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+	repoPath, err := parseRepoPath(r.URL.Path[len(APIPrefix+"repos/"):])
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	s.serveRepo(w, r, repoPath)
+}
+
+// serveRepo runs the requested checks on the repo and writes the result in the
+// negotiated format.
+func (s *Server) serveRepo(w http.ResponseWriter, r *http.Request, repoParam string) {
+	format, err := negotiateFormat(r)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	params, err := s.parseParams(r, repoParam)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	s.opts.Logger.Info(params.repo.URL())
+	result, cached, err := s.scan(r.Context(), &params)
+	if err != nil {
+		s.opts.Logger.Error(err)
+		s.writeError(w, r, fromScoreError(err))
+		return
+	}
+	if cached {
+		w.Header().Set(xCache, "HIT")
+	} else {
+		w.Header().Set(xCache, "MISS")
+	}
+	s.writeResult(w, r, format, &result, params.details)
+}
@@ -0,0 +1,255 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ossf/scorecard/v2/checker"
+	sce "github.com/ossf/scorecard/v2/errors"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+// fakeScorer records the checks it was asked to run and returns err, if set.
+type fakeScorer struct {
+	err    error
+	called bool
+	checks []string
+}
+
+func (f *fakeScorer) score(ctx context.Context, repo repos.RepoURL,
+	checks checker.CheckNameToFnMap) (pkg.ScorecardResult, error) {
+	f.called = true
+	if f.err != nil {
+		return pkg.ScorecardResult{}, f.err
+	}
+	result := pkg.ScorecardResult{Repo: repo.URL(), Date: "2021-08-01"}
+	for name := range checks {
+		f.checks = append(f.checks, name)
+		result.Checks = append(result.Checks, checker.CheckResult{
+			Name: name, Pass: true, Confidence: checker.MaxResultConfidence, Details: []string{"detail"},
+		})
+	}
+	sort.Strings(f.checks)
+	return result, nil
+}
+
+func newTestServer(t *testing.T, scorer *fakeScorer) *Server {
+	t.Helper()
+	check := func(c *checker.CheckRequest) checker.CheckResult { return checker.CheckResult{} }
+	srv, err := New(Options{
+		Logger:   zap.NewNop().Sugar(),
+		Score:    scorer.score,
+		Checks:   checker.CheckNameToFnMap{"Code-Review": check, "Signed-Releases": check},
+		LogLevel: zapcore.InfoLevel,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return srv
+}
+
+func TestServeRepo(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		accept      string
+		scoreErr    error
+		wantCode    int
+		wantType    string
+		wantClass   string
+		wantChecks  []string
+		wantDetails bool
+	}{
+		{
+			name:       "all checks",
+			target:     "/api/v1/repos/github.com/ossf/scorecard",
+			wantCode:   http.StatusOK,
+			wantType:   contentTypeJSON,
+			wantChecks: []string{"Code-Review", "Signed-Releases"},
+		},
+		{
+			name:        "selected checks with details",
+			target:      "/api/v1/repos/github.com/ossf/scorecard?checks=code-review&details=true",
+			accept:      "application/json",
+			wantCode:    http.StatusOK,
+			wantType:    contentTypeJSON,
+			wantChecks:  []string{"Code-Review"},
+			wantDetails: true,
+		},
+		{
+			name:       "legacy repo parameter",
+			target:     "/?repo=github.com/ossf/scorecard&checks=Code-Review,Signed-Releases",
+			wantCode:   http.StatusOK,
+			wantType:   contentTypeJSON,
+			wantChecks: []string{"Code-Review", "Signed-Releases"},
+		},
+		{
+			name:       "browser",
+			target:     "/api/v1/repos/github.com/ossf/scorecard",
+			accept:     "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			wantCode:   http.StatusOK,
+			wantType:   contentTypeHTML,
+			wantChecks: []string{"Code-Review", "Signed-Releases"},
+		},
+		{
+			name:      "not acceptable",
+			target:    "/api/v1/repos/github.com/ossf/scorecard",
+			accept:    "image/png",
+			wantCode:  http.StatusNotAcceptable,
+			wantType:  contentTypeJSON,
+			wantClass: "ErrNotAcceptable",
+		},
+		{
+			name:      "invalid check",
+			target:    "/api/v1/repos/github.com/ossf/scorecard?checks=Fuzzing",
+			wantCode:  http.StatusBadRequest,
+			wantType:  contentTypeJSON,
+			wantClass: "ErrInvalidRequest",
+		},
+		{
+			name:      "invalid details",
+			target:    "/api/v1/repos/github.com/ossf/scorecard?details=maybe",
+			wantCode:  http.StatusBadRequest,
+			wantType:  contentTypeJSON,
+			wantClass: "ErrInvalidRequest",
+		},
+		{
+			name:      "unsupported host",
+			target:    "/api/v1/repos/gitlab.com/ossf/scorecard",
+			wantCode:  http.StatusBadRequest,
+			wantType:  contentTypeJSON,
+			wantClass: "ErrUnsupportedHost",
+		},
+		{
+			name:      "missing repo",
+			target:    "/api/v1/repos/github.com/ossf",
+			wantCode:  http.StatusNotFound,
+			wantType:  contentTypeJSON,
+			wantClass: "ErrRouteNotFound",
+		},
+		{
+			name:      "missing legacy repo parameter",
+			target:    "/",
+			wantCode:  http.StatusBadRequest,
+			wantType:  contentTypeJSON,
+			wantClass: "ErrInvalidRequest",
+		},
+		{
+			name:      "method not allowed",
+			method:    http.MethodPost,
+			target:    "/api/v1/repos/github.com/ossf/scorecard",
+			wantCode:  http.StatusMethodNotAllowed,
+			wantType:  contentTypeJSON,
+			wantClass: "ErrMethodNotAllowed",
+		},
+		{
+			name:      "repo not found",
+			target:    "/api/v1/repos/github.com/ossf/missing",
+			scoreErr:  sce.Create(sce.ErrNotFound, "Repositories.Get"),
+			wantCode:  http.StatusNotFound,
+			wantType:  contentTypeJSON,
+			wantClass: "ErrNotFound",
+		},
+		{
+			name:      "rate limited",
+			target:    "/api/v1/repos/github.com/ossf/scorecard",
+			scoreErr:  sce.Create(sce.ErrRateLimited, "Repositories.Get"),
+			wantCode:  http.StatusServiceUnavailable,
+			wantType:  contentTypeJSON,
+			wantClass: "ErrRateLimited",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			scorer := &fakeScorer{err: tt.scoreErr}
+			srv := newTestServer(t, scorer)
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.wantType) {
+				t.Errorf("Content-Type = %s, want %s", got, tt.wantType)
+			}
+			if tt.wantClass != "" {
+				var body errorBody
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatalf("json.Unmarshal: %v", err)
+				}
+				if body.Error.Class != tt.wantClass || body.Error.Code != tt.wantCode {
+					t.Errorf("error = %+v, want class %s and code %d", body.Error, tt.wantClass, tt.wantCode)
+				}
+				if scorer.called && tt.scoreErr == nil {
+					t.Errorf("checks ran on an invalid request")
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.wantChecks, scorer.checks); diff != "" {
+				t.Errorf("unexpected checks (-want +got):\n%s", diff)
+			}
+			if tt.wantType != contentTypeJSON {
+				return
+			}
+			var result pkg.ScorecardResult
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("json.Unmarshal: %v", err)
+			}
+			if result.Repo != "github.com/ossf/scorecard" {
+				t.Errorf("Repo = %s, want github.com/ossf/scorecard", result.Repo)
+			}
+			if hasDetails := len(result.Checks) > 0 && result.Checks[0].Details != nil; hasDetails != tt.wantDetails {
+				t.Errorf("details returned = %v, want %v", hasDetails, tt.wantDetails)
+			}
+		})
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t, &fakeScorer{})
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.yaml", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !strings.Contains(rec.Body.String(), "/repos/{host}/{owner}/{repo}:") {
+		t.Errorf("OpenAPI description does not describe the repos endpoint")
+	}
+}
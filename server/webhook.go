@@ -0,0 +1,140 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v32/github"
+)
+
+const (
+	// maxWebhookSize is the maximum size of webhook payloads sent by GitHub.
+	maxWebhookSize = 25 << 20
+
+	signatureHeader       = "X-Hub-Signature"
+	signatureSHA256Header = "X-Hub-Signature-256"
+)
+
+var errInvalidSignature = errors.New("invalid signature")
+
+// webhookResponse is the body of responses to webhook deliveries which do not queue a job.
+type webhookResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// handleGitHubWebhook rescores the repo of `push` events to its default branch, and
+// of `release` and `repository` events. The deliveries must be signed with the
+// webhook secret. The rescoring jobs also store their results in the result cache.
+func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	payload, err := s.validateWebhook(r)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	eventType := github.WebHookType(r)
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		s.writeJSON(w, http.StatusOK, webhookResponse{Status: "ignored", Reason: err.Error()})
+		return
+	}
+
+	var repoURL string
+	switch event := event.(type) {
+	case *github.PingEvent:
+		s.writeJSON(w, http.StatusOK, webhookResponse{Status: "pong"})
+		return
+	case *github.PushEvent:
+		repo := event.GetRepo()
+		defaultBranch := repo.GetDefaultBranch()
+		if defaultBranch == "" {
+			defaultBranch = repo.GetMasterBranch()
+		}
+		if event.GetDeleted() || event.GetRef() != "refs/heads/"+defaultBranch {
+			s.writeJSON(w, http.StatusOK, webhookResponse{
+				Status: "ignored",
+				Reason: fmt.Sprintf("%s is not the default branch", event.GetRef()),
+			})
+			return
+		}
+		repoURL = repo.GetHTMLURL()
+	case *github.ReleaseEvent:
+		repoURL = event.GetRepo().GetHTMLURL()
+	case *github.RepositoryEvent:
+		if event.GetAction() == "deleted" {
+			s.writeJSON(w, http.StatusOK, webhookResponse{Status: "ignored", Reason: "repository deleted"})
+			return
+		}
+		repoURL = event.GetRepo().GetHTMLURL()
+	default:
+		s.writeJSON(w, http.StatusOK, webhookResponse{
+			Status: "ignored",
+			Reason: fmt.Sprintf("unsupported event: %s", eventType),
+		})
+		return
+	}
+
+	params, err := s.makeParams(repoURL, nil)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	s.opts.Logger.Infof("rescoring %s on %s event %s", params.repo.URL(), eventType, github.DeliveryID(r))
+	job, err := s.jobs.submitUnlessQueued(&params)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", APIPrefix+"scans/"+job.ID)
+	s.writeJSON(w, http.StatusAccepted, job)
+}
+
+// validateWebhook verifies the signature of the delivery against the webhook secret,
+// preferring SHA-256 signatures, and returns its JSON payload.
+func (s *Server) validateWebhook(r *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxWebhookSize))
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, errInvalidRequest, fmt.Sprintf("error reading body: %v", err))
+	}
+	signature := r.Header.Get(signatureSHA256Header)
+	if signature == "" {
+		signature = r.Header.Get(signatureHeader)
+	}
+	if err := github.ValidateSignature(signature, body, []byte(s.opts.WebhookSecret)); err != nil {
+		return nil, newError(http.StatusUnauthorized, errInvalidSignature, err.Error())
+	}
+
+	switch contentType := r.Header.Get("Content-Type"); contentType {
+	case "application/json":
+		return body, nil
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, newError(http.StatusBadRequest, errInvalidRequest, fmt.Sprintf("invalid form: %v", err))
+		}
+		return []byte(form.Get("payload")), nil
+	default:
+		return nil, newError(http.StatusUnsupportedMediaType, errInvalidRequest,
+			fmt.Sprintf("unsupported Content-Type: %q", contentType))
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2021 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ossf/scorecard/v2/checker"
+	"github.com/ossf/scorecard/v2/pkg"
+	"github.com/ossf/scorecard/v2/repos"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+func sign(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookServer(t *testing.T, score ScoreFunc) *Server {
+	t.Helper()
+	srv, err := New(Options{
+		Logger: zap.NewNop().Sugar(),
+		Score:  score,
+		Checks: checker.CheckNameToFnMap{
+			"Code-Review": func(c *checker.CheckRequest) checker.CheckResult {
+				return checker.CheckResult{Name: "Code-Review", Pass: true}
+			},
+		},
+		Cache: NewResultCache(time.Hour, ""),
+		Head: func(ctx context.Context, repo repos.RepoURL) (string, error) {
+			return "0000000000000000000000000000000000000001", nil
+		},
+		Jobs:          &JobOptions{Workers: 1},
+		WebhookSecret: testWebhookSecret,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// deliver posts the recorded payload as the event, signed with the secret.
+func deliver(t *testing.T, srv *Server, event, payloadFile, secret string) *httptest.ResponseRecorder {
+	t.Helper()
+	payload, err := ioutil.ReadFile(filepath.Join("testdata", "webhooks", payloadFile))
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/github", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	if secret != "" {
+		req.Header.Set(signatureSHA256Header, sign(payload, secret))
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGitHubWebhook(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		event      string
+		payload    string
+		secret     string
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "push to default branch", event: "push", payload: "push.json", wantCode: http.StatusAccepted},
+		{
+			name: "push to other branch", event: "push", payload: "push_branch.json",
+			wantCode: http.StatusOK, wantStatus: "ignored",
+		},
+		{name: "release", event: "release", payload: "release.json", wantCode: http.StatusAccepted},
+		{name: "repository renamed", event: "repository", payload: "repository_renamed.json", wantCode: http.StatusAccepted},
+		{
+			name: "repository deleted", event: "repository", payload: "repository_deleted.json",
+			wantCode: http.StatusOK, wantStatus: "ignored",
+		},
+		{name: "ping", event: "ping", payload: "ping.json", wantCode: http.StatusOK, wantStatus: "pong"},
+		{
+			name: "unsupported event", event: "issues", payload: "release.json",
+			wantCode: http.StatusOK, wantStatus: "ignored",
+		},
+		{name: "wrong secret", event: "push", payload: "push.json", secret: "wrong", wantCode: http.StatusUnauthorized},
+		{name: "unsigned", event: "push", payload: "push.json", secret: "-", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			release := make(chan struct{})
+			t.Cleanup(func() { close(release) })
+			srv := newWebhookServer(t, blockingScore(release))
+			secret := tt.secret
+			switch secret {
+			case "":
+				secret = testWebhookSecret
+			case "-":
+				secret = ""
+			}
+			rec := deliver(t, srv, tt.event, tt.payload, secret)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			switch {
+			case tt.wantStatus != "":
+				var resp webhookResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("json.Unmarshal: %v", err)
+				}
+				if resp.Status != tt.wantStatus {
+					t.Errorf("status = %s, want %s", resp.Status, tt.wantStatus)
+				}
+			case rec.Code == http.StatusAccepted:
+				var job Job
+				if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+					t.Fatalf("json.Unmarshal: %v", err)
+				}
+				if job.Repo != "github.com/ossf-tests/scorecard-check-webhook" {
+					t.Errorf("job repo = %s", job.Repo)
+				}
+			}
+		})
+	}
+}
+
+func TestGitHubWebhookRescore(t *testing.T) {
+	t.Parallel()
+	var scans int32
+	release := make(chan struct{})
+	blocking := blockingScore(release)
+	srv := newWebhookServer(t, func(ctx context.Context, repo repos.RepoURL,
+		checks checker.CheckNameToFnMap) (pkg.ScorecardResult, error) {
+		atomic.AddInt32(&scans, 1)
+		return blocking(ctx, repo, checks)
+	})
+
+	decode := func(rec *httptest.ResponseRecorder) Job {
+		var job Job
+		if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+			t.Fatalf("json.Unmarshal: %v", err)
+		}
+		return job
+	}
+	running := decode(deliver(t, srv, "push", "push.json", testWebhookSecret))
+	waitForState(t, srv, running.ID, JobRunning)
+	// Deliveries while a rescore is queued share it.
+	queued := decode(deliver(t, srv, "push", "push.json", testWebhookSecret))
+	if again := decode(deliver(t, srv, "release", "release.json", testWebhookSecret)); again.ID != queued.ID {
+		t.Errorf("delivery queued job %s, want existing job %s", again.ID, queued.ID)
+	}
+
+	close(release)
+	waitForState(t, srv, running.ID, JobSucceeded)
+	waitForState(t, srv, queued.ID, JobSucceeded)
+	if got := atomic.LoadInt32(&scans); got != 2 {
+		t.Errorf("%d scans, want 2", got)
+	}
+
+	// The latest result is served from the cache.
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+		"/api/v1/repos/github.com/ossf-tests/scorecard-check-webhook", nil))
+	if got := rec.Header().Get(xCache); got != "HIT" {
+		t.Errorf("%s = %s, want HIT", xCache, got)
+	}
+	if got := atomic.LoadInt32(&scans); got != 2 {
+		t.Errorf("%d scans after GET, want 2", got)
+	}
+}
@@ -27,4 +27,10 @@ var (
 		stats.UnitSeconds)
 	// HTTPRequests measures the count of HTTP requests.
 	HTTPRequests = stats.Int64("HTTPRequests", "Measures the count of HTTP requests", stats.UnitDimensionless)
+	// HTTPRetryWaitInMs measures the time spent waiting to retry HTTP requests.
+	HTTPRetryWaitInMs = stats.Int64("HTTPRetryWaitInMs", "Measures the time spent waiting to retry HTTP requests",
+		stats.UnitMilliseconds)
+	// APIRequests measures the count of requests to the serve API.
+	APIRequests = stats.Int64("APIRequests", "Measures the count of requests to the serve API",
+		stats.UnitDimensionless)
 )
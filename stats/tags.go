@@ -25,4 +25,41 @@ var (
 	Repo = tag.MustNewKey("repo")
 	// RequestTag is the tag key for the request type.
 	RequestTag = tag.MustNewKey("requestTag")
+	// RetryReason is the tag key for the reason an HTTP request is retried.
+	RetryReason = tag.MustNewKey("retryReason")
+	// APIKey is the tag key for the name of the API key of a request to the serve API.
+	APIKey = tag.MustNewKey("apiKey")
+	// APIResult is the tag key for whether a request to the serve API was let through.
+	APIResult = tag.MustNewKey("apiResult")
+)
+
+// Values of RequestTag, which can be used to compute the HTTP cache hit rate.
+const (
+	// RequestTagRequested is used for requests which were not served from the cache.
+	RequestTagRequested = "requested"
+	// RequestTagCacheHit is used for requests served from the cache without contacting the server.
+	RequestTagCacheHit = "X-From-Cache"
+	// RequestTagCacheRevalidated is used for requests served from the cache after the server
+	// confirmed the cached response is unchanged with a 304 Not Modified.
+	RequestTagCacheRevalidated = "revalidated"
+)
+
+// Values of RetryReason.
+const (
+	// RetryReasonPrimaryRateLimit is used when all tokens exhausted their rate limit.
+	RetryReasonPrimaryRateLimit = "Primary rate limit exceeded"
+	// RetryReasonSecondaryRateLimit is used when GitHub's secondary rate limit was hit.
+	RetryReasonSecondaryRateLimit = "Secondary rate limit exceeded"
+	// RetryReasonServerError is used for 5xx responses.
+	RetryReasonServerError = "Server error"
+)
+
+// Values of APIResult.
+const (
+	// APIResultAllowed is used for requests within the quota of their API key.
+	APIResultAllowed = "allowed"
+	// APIResultRateLimited is used for requests rejected because their API key exceeded its quota.
+	APIResultRateLimited = "rate limited"
+	// APIResultUnauthorized is used for requests rejected because of a missing or unknown API key.
+	APIResultUnauthorized = "unauthorized"
 )
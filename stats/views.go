@@ -88,4 +88,22 @@ var (
 		TagKeys:     []tag.Key{CheckName, RequestTag},
 		Aggregation: view.Count(),
 	}
+
+	// HTTPRetryWaitTime tracks the total time spent waiting to retry HTTP requests.
+	HTTPRetryWaitTime = view.View{
+		Name:        "HTTPRetryWaitTime",
+		Description: "Time spent waiting to retry HTTP requests per retry reason",
+		Measure:     HTTPRetryWaitInMs,
+		TagKeys:     []tag.Key{RetryReason},
+		Aggregation: view.Sum(),
+	}
+
+	// APIKeyRequests tracks the requests to the serve API per API key.
+	APIKeyRequests = view.View{
+		Name:        "APIKeyRequests",
+		Description: "Requests to the serve API per API key and result",
+		Measure:     APIRequests,
+		TagKeys:     []tag.Key{APIKey, APIResult},
+		Aggregation: view.Count(),
+	}
 )